@@ -0,0 +1,259 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
+)
+
+// State is the current position of the breaker
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrOpen is matched by errors.Is for every call rejected by the breaker
+var ErrOpen = errors.New("circuit breaker is open")
+
+// OpenError is returned when a call is rejected, carrying how long until the next probe is allowed
+type OpenError struct {
+	Name       string
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit breaker %s is open, retry after %s", e.Name, e.RetryAfter)
+}
+
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Snapshot is a point-in-time view of the breaker used for diagnostics
+type Snapshot struct {
+	Name              string     `json:"name"`
+	State             string     `json:"state"`
+	Requests          int        `json:"requests"`
+	Failures          int        `json:"failures"`
+	FailureRatio      float64    `json:"failure_ratio"`
+	OpenedAt          *time.Time `json:"opened_at,omitempty"`
+	RetryAfterSeconds int        `json:"retry_after_seconds,omitempty"`
+}
+
+// CircuitBreaker trips open when the failure ratio over a window crosses the configured threshold,
+// rejects calls during the cool-down and then lets a limited number of probes through (half-open)
+type CircuitBreaker struct {
+	name   string
+	config config.CircuitBreakerConfig
+	now    func() time.Time
+
+	mu            sync.Mutex
+	state         State
+	requests      int
+	failures      int
+	windowStart   time.Time
+	openedAt      time.Time
+	halfOpenCalls int
+	// generation changes on every state transition, so outcomes of calls admitted
+	// before it are discarded instead of being applied to the new state
+	generation uint64
+}
+
+func NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	return newCircuitBreaker(name, cfg, time.Now)
+}
+
+func newCircuitBreaker(name string, cfg config.CircuitBreakerConfig, now func() time.Time) *CircuitBreaker {
+	if cfg.HalfOpenMaxRequests <= 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+	return &CircuitBreaker{
+		name:        name,
+		config:      cfg,
+		now:         now,
+		windowStart: now(),
+	}
+}
+
+// Execute runs fn if the breaker allows it and records the outcome.
+// Errors for which isFailure returns false (e.g. caller cancellation) do not count against the upstream.
+func (cb *CircuitBreaker) Execute(fn func() error, isFailure func(error) bool) error {
+	generation, err := cb.allow()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	switch {
+	case err == nil:
+		cb.record(generation, outcomeSuccess)
+	case isFailure == nil || isFailure(err):
+		cb.record(generation, outcomeFailure)
+	default:
+		cb.record(generation, outcomeIgnored)
+	}
+	return err
+}
+
+// allow admits a call and returns the generation it was admitted in
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+
+	switch cb.state {
+	case StateOpen:
+		retryAfter := cb.openedAt.Add(cb.config.CoolDown).Sub(now)
+		if retryAfter > 0 {
+			return 0, &OpenError{Name: cb.name, RetryAfter: retryAfter}
+		}
+		cb.setState(StateHalfOpen, now)
+		fallthrough
+	case StateHalfOpen:
+		if cb.halfOpenCalls >= cb.config.HalfOpenMaxRequests {
+			return 0, &OpenError{Name: cb.name, RetryAfter: time.Second}
+		}
+		cb.halfOpenCalls++
+	default:
+		if cb.config.Window > 0 && now.Sub(cb.windowStart) >= cb.config.Window {
+			cb.resetCounts(now)
+		}
+	}
+
+	return cb.generation, nil
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+// record applies the outcome of a call admitted in generation. A call that outlived a state
+// transition says nothing about the new state (e.g. a slow call admitted while closed must
+// not close the breaker in place of the half-open probe), so its outcome is dropped.
+func (cb *CircuitBreaker) record(generation uint64, result outcome) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+
+	now := cb.now()
+	failed := result == outcomeFailure
+
+	switch cb.state {
+	case StateHalfOpen:
+		if result == outcomeIgnored {
+			// Free the probe slot without deciding anything
+			cb.halfOpenCalls--
+			return
+		}
+		if failed {
+			cb.setState(StateOpen, now)
+		} else {
+			cb.setState(StateClosed, now)
+		}
+	case StateClosed:
+		if result == outcomeIgnored {
+			return
+		}
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.requests >= cb.config.MinRequests && cb.ratio() >= cb.config.FailureRatio {
+			cb.setState(StateOpen, now)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) setState(state State, now time.Time) {
+	cb.state = state
+	cb.generation++
+	cb.halfOpenCalls = 0
+	if state == StateOpen {
+		cb.openedAt = now
+	}
+	if state == StateClosed {
+		cb.resetCounts(now)
+	}
+}
+
+func (cb *CircuitBreaker) resetCounts(now time.Time) {
+	cb.requests = 0
+	cb.failures = 0
+	cb.windowStart = now
+}
+
+func (cb *CircuitBreaker) ratio() float64 {
+	if cb.requests == 0 {
+		return 0
+	}
+	return float64(cb.failures) / float64(cb.requests)
+}
+
+// State returns the current state, moving from open to half-open once the cool-down has elapsed
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.currentState(cb.now())
+}
+
+// currentState reports the state at now; callers must hold mu
+func (cb *CircuitBreaker) currentState(now time.Time) State {
+	if cb.state == StateOpen && !now.Before(cb.openedAt.Add(cb.config.CoolDown)) {
+		return StateHalfOpen
+	}
+	return cb.state
+}
+
+// Snapshot returns the breaker state and counters for the diagnostics endpoint, all read at once
+func (cb *CircuitBreaker) Snapshot() Snapshot {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	state := cb.currentState(now)
+
+	snapshot := Snapshot{
+		Name:         cb.name,
+		State:        state.String(),
+		Requests:     cb.requests,
+		Failures:     cb.failures,
+		FailureRatio: cb.ratio(),
+	}
+
+	if state == StateOpen {
+		openedAt := cb.openedAt
+		snapshot.OpenedAt = &openedAt
+		retryAfter := cb.openedAt.Add(cb.config.CoolDown).Sub(now)
+		snapshot.RetryAfterSeconds = int((retryAfter + time.Second - 1) / time.Second)
+	}
+
+	return snapshot
+}
@@ -0,0 +1,172 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker() (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cb := newCircuitBreaker("test", config.CircuitBreakerConfig{
+		FailureRatio:        0.5,
+		MinRequests:         4,
+		Window:              time.Minute,
+		CoolDown:            10 * time.Second,
+		HalfOpenMaxRequests: 1,
+	}, clock.Now)
+	return cb, clock
+}
+
+var errUpstream = errors.New("upstream error")
+
+func succeed() error { return nil }
+func fail() error    { return errUpstream }
+
+func TestCircuitBreaker_OpensOnFailureRatio(t *testing.T) {
+	cb, _ := newTestBreaker()
+
+	// Below MinRequests the breaker stays closed even with 100% failures
+	assert.Equal(t, errUpstream, cb.Execute(fail, nil))
+	assert.Equal(t, errUpstream, cb.Execute(fail, nil))
+	assert.NoError(t, cb.Execute(succeed, nil))
+	assert.Equal(t, StateClosed, cb.State())
+
+	// 3 failures out of 4 requests crosses the 0.5 ratio
+	assert.Equal(t, errUpstream, cb.Execute(fail, nil))
+	assert.Equal(t, StateOpen, cb.State())
+
+	// Calls are rejected without running fn
+	called := false
+	err := cb.Execute(func() error { called = true; return nil }, nil)
+	assert.False(t, called)
+	assert.ErrorIs(t, err, ErrOpen)
+
+	var openErr *OpenError
+	assert.ErrorAs(t, err, &openErr)
+	assert.Equal(t, 10*time.Second, openErr.RetryAfter)
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	cb, clock := newTestBreaker()
+	for i := 0; i < 4; i++ {
+		cb.Execute(fail, nil)
+	}
+	assert.Equal(t, StateOpen, cb.State())
+
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, StateHalfOpen, cb.State())
+
+	// A failed probe re-opens the breaker for another cool-down
+	assert.Equal(t, errUpstream, cb.Execute(fail, nil))
+	assert.Equal(t, StateOpen, cb.State())
+
+	clock.Advance(10 * time.Second)
+
+	// A successful probe closes it
+	assert.NoError(t, cb.Execute(succeed, nil))
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, 0, cb.Snapshot().Failures)
+}
+
+func TestCircuitBreaker_HalfOpenLimitsConcurrentProbes(t *testing.T) {
+	cb, clock := newTestBreaker()
+	for i := 0; i < 4; i++ {
+		cb.Execute(fail, nil)
+	}
+	clock.Advance(10 * time.Second)
+
+	err := cb.Execute(func() error {
+		// A second call while the probe is in flight is rejected
+		assert.ErrorIs(t, cb.Execute(succeed, nil), ErrOpen)
+		return nil
+	}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_StaleOutcomesAreDiscarded(t *testing.T) {
+	cb, clock := newTestBreaker()
+
+	// A slow call admitted while closed outlives the trip, the cool-down and the start of a probe
+	err := cb.Execute(func() error {
+		for i := 0; i < 4; i++ {
+			cb.Execute(fail, nil)
+		}
+		clock.Advance(10 * time.Second)
+
+		// The probe is canceled, which frees its slot without deciding anything
+		probeErr := cb.Execute(func() error {
+			// Another probe is still rejected while the first one is in flight
+			assert.ErrorIs(t, cb.Execute(succeed, nil), ErrOpen)
+			return context.Canceled
+		}, func(error) bool { return false })
+		assert.ErrorIs(t, probeErr, context.Canceled)
+		return nil
+	}, nil)
+	assert.NoError(t, err)
+
+	// The slow call's success neither closed the breaker nor freed a probe slot twice
+	assert.Equal(t, StateHalfOpen, cb.State())
+	assert.Equal(t, errUpstream, cb.Execute(fail, nil))
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestCircuitBreaker_IgnoredErrors(t *testing.T) {
+	cb, _ := newTestBreaker()
+	ignoreCanceled := func(err error) bool { return !errors.Is(err, context.Canceled) }
+
+	for i := 0; i < 10; i++ {
+		err := cb.Execute(func() error { return context.Canceled }, ignoreCanceled)
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, 0, cb.Snapshot().Requests)
+}
+
+func TestCircuitBreaker_WindowResetsCounters(t *testing.T) {
+	cb, clock := newTestBreaker()
+	cb.Execute(fail, nil)
+	cb.Execute(fail, nil)
+	cb.Execute(fail, nil)
+
+	clock.Advance(time.Minute)
+
+	// The old failures fall out of the window
+	cb.Execute(fail, nil)
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, 1, cb.Snapshot().Failures)
+}
+
+func TestCircuitBreaker_Snapshot(t *testing.T) {
+	cb, clock := newTestBreaker()
+	for i := 0; i < 4; i++ {
+		cb.Execute(fail, nil)
+	}
+	clock.Advance(2500 * time.Millisecond)
+
+	snapshot := cb.Snapshot()
+
+	assert.Equal(t, "test", snapshot.Name)
+	assert.Equal(t, "open", snapshot.State)
+	assert.NotNil(t, snapshot.OpenedAt)
+	assert.Equal(t, 8, snapshot.RetryAfterSeconds)
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+type ExportQuotesUseCase struct {
+	quoteRepository domain.QuoteRepository
+}
+
+func NewExportQuotesUseCase(quoteRepository domain.QuoteRepository) *ExportQuotesUseCase {
+	return &ExportQuotesUseCase{
+		quoteRepository: quoteRepository,
+	}
+}
+
+// Execute streams one row per carrier offer of the filtered quotes to write, oldest quote first.
+// With a carrier filter only that carrier's offers are written. An error from write stops the export.
+func (uc *ExportQuotesUseCase) Execute(ctx context.Context, filter domain.QuoteExportFilter, write func(domain.QuoteExportRow) error) error {
+	return uc.quoteRepository.ExportQuotes(ctx, filter, func(quote domain.QuoteResponse) error {
+		for _, row := range quote.ExportRows(filter.CarrierName) {
+			if err := write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
+	"gorm.io/gorm"
+)
+
+func TestExportQuotesUseCase_Execute(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+
+	filter := domain.QuoteExportFilter{CarrierName: "correios"}
+	stored := []domain.QuoteResponse{
+		{Model: gorm.Model{ID: 1}, Carriers: []domain.Carrier{{Name: "Correios", Price: 20.0}, {Name: "EXPRESSO FR", Price: 17.0}}},
+		{Model: gorm.Model{ID: 2}, Carriers: []domain.Carrier{{Name: "Correios", Price: 22.0}, {Name: "Correios", Service: "SEDEX", Price: 30.0}}},
+	}
+	mockRepo.On("ExportQuotes", mock.Anything, filter).Return(stored, nil)
+
+	useCase := usecases.NewExportQuotesUseCase(mockRepo)
+
+	var rows []domain.QuoteExportRow
+	err := useCase.Execute(context.Background(), filter, func(row domain.QuoteExportRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+
+	// Only the filtered carrier's offers are written, one row each
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, []uint{1, 2, 2}, []uint{rows[0].QuoteID, rows[1].QuoteID, rows[2].QuoteID})
+	assert.Equal(t, 30.0, rows[2].Price)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportQuotesUseCase_Execute_WriteError(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+
+	stored := []domain.QuoteResponse{
+		{Model: gorm.Model{ID: 1}, Carriers: []domain.Carrier{{Name: "Correios"}, {Name: "EXPRESSO FR"}}},
+	}
+	mockRepo.On("ExportQuotes", mock.Anything, domain.QuoteExportFilter{}).Return(stored, nil)
+
+	useCase := usecases.NewExportQuotesUseCase(mockRepo)
+
+	writeErr := errors.New("broken pipe")
+	calls := 0
+	err := useCase.Execute(context.Background(), domain.QuoteExportFilter{}, func(domain.QuoteExportRow) error {
+		calls++
+		return writeErr
+	})
+
+	assert.Equal(t, writeErr, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,6 @@
+package usecases
+
+// SetQuoteFlightJoinHook lets tests wait until concurrent requests have joined an in-flight quote
+func SetQuoteFlightJoinHook(uc *GetShippingQuotationUseCase, hook func()) {
+	uc.flights.joined = hook
+}
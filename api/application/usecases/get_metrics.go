@@ -2,39 +2,48 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 )
 
 type GetMetricsUseCase struct {
 	metricsRepository domain.MetricsRepository
+	metricsCache      *MetricsCache
 }
 
-func NewGetMetricsUseCase(metricsRepository domain.MetricsRepository) *GetMetricsUseCase {
+// NewGetMetricsUseCase creates the use case. A nil metricsCache disables caching.
+func NewGetMetricsUseCase(metricsRepository domain.MetricsRepository, metricsCache *MetricsCache) *GetMetricsUseCase {
 	return &GetMetricsUseCase{
 		metricsRepository: metricsRepository,
+		metricsCache:      metricsCache,
 	}
 }
 
-func (uc *GetMetricsUseCase) Execute(ctx context.Context, lastQuotes int) (*domain.MetricsResponse, error) {
-	// Possivel uso de goroutines
-	// Get metrics from repository
-	// var wg sync.WaitGroup
-	// var metricsResult *domain.MetricsResponse
-	// var metricsErr error
-	//
-	// wg.Add(1)
-	// go func() {
-	//     defer wg.Done()
-	//     metricsResult, metricsErr = uc.metricsRepository.GetMetrics(ctx, lastQuotes)
-	// }()
-	//
-	// // Fetch other data concurrently here
-	//
-	// wg.Wait()
-	// if metricsErr != nil {
-	//     return nil, metricsErr
-	// }
-
-	return uc.metricsRepository.GetMetrics(ctx, lastQuotes)
+// Execute resolves relative periods against the current time, computes the metrics
+// and echoes the applied window in the response. Responses are cached by the query as
+// received, so a relative period is served with the window resolved when it was computed.
+func (uc *GetMetricsUseCase) Execute(ctx context.Context, query domain.MetricsQuery) (*domain.MetricsResponse, error) {
+	var cacheKey string
+	if uc.metricsCache != nil {
+		cached, key, ok := uc.metricsCache.Get(ctx, query)
+		if ok {
+			return cached, nil
+		}
+		cacheKey = key
+	}
+
+	query = query.Resolve(time.Now())
+
+	metrics, err := uc.metricsRepository.GetMetrics(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.Window = query.Window()
+
+	if uc.metricsCache != nil {
+		uc.metricsCache.Set(ctx, cacheKey, metrics)
+	}
+	return metrics, nil
 }
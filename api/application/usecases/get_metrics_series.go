@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+type GetMetricsSeriesUseCase struct {
+	metricsRepository domain.MetricsRepository
+}
+
+func NewGetMetricsSeriesUseCase(metricsRepository domain.MetricsRepository) *GetMetricsSeriesUseCase {
+	return &GetMetricsSeriesUseCase{
+		metricsRepository: metricsRepository,
+	}
+}
+
+// Execute resolves relative periods against the current time, computes the per-carrier
+// series and echoes the applied window in the response
+func (uc *GetMetricsSeriesUseCase) Execute(ctx context.Context, query domain.MetricsSeriesQuery) (*domain.MetricsSeries, error) {
+	query = query.Resolve(time.Now())
+
+	series, err := uc.metricsRepository.GetMetricsSeries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Window = query.Window()
+	return series, nil
+}
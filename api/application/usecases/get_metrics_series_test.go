@@ -0,0 +1,61 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
+)
+
+func TestGetMetricsSeriesUseCase_Execute_Success(t *testing.T) {
+	mockRepo := new(mocks.MockMetricsRepository)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	query := domain.MetricsSeriesQuery{
+		MetricsQuery: domain.MetricsQuery{From: &from, To: &to},
+		Bucket:       domain.BucketDay,
+	}
+	mockResponse := &domain.MetricsSeries{
+		Bucket: domain.BucketDay,
+		Carriers: []domain.CarrierSeries{
+			{CarrierName: "EXPRESSO FR", Points: []domain.MetricsPoint{{BucketStart: from, TotalQuotes: 2, AverageShippingPrice: 17.5}}},
+		},
+	}
+	mockRepo.On("GetMetricsSeries", mock.Anything, query).Return(mockResponse, nil)
+
+	useCase := usecases.NewGetMetricsSeriesUseCase(mockRepo)
+
+	result, err := useCase.Execute(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Carriers, 1)
+	assert.Equal(t, "UTC", result.Window.Timezone)
+	assert.True(t, from.Equal(*result.Window.From))
+	assert.True(t, to.Equal(*result.Window.To))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMetricsSeriesUseCase_Execute_Error(t *testing.T) {
+	mockRepo := new(mocks.MockMetricsRepository)
+
+	expectedError := errors.New("database error")
+	mockRepo.On("GetMetricsSeries", mock.Anything, mock.Anything).Return(nil, expectedError)
+
+	useCase := usecases.NewGetMetricsSeriesUseCase(mockRepo)
+
+	result, err := useCase.Execute(context.Background(), domain.MetricsSeriesQuery{
+		MetricsQuery: domain.MetricsQuery{Period: domain.PeriodToday},
+		Bucket:       domain.BucketHour,
+	})
+
+	assert.Equal(t, expectedError, err)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,8 +2,10 @@ package usecases_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -39,14 +41,14 @@ func TestGetMetricsUseCase_Execute_Success(t *testing.T) {
 	}
 
 	// Setup expectations
-	lastQuotes := 10
-	mockRepo.On("GetMetrics", mock.Anything, lastQuotes).Return(mockResponse, nil)
+	query := domain.MetricsQuery{LastQuotes: 10}
+	mockRepo.On("GetMetrics", mock.Anything, query).Return(mockResponse, nil)
 
 	// Create the use case with the mock repository
-	useCase := usecases.NewGetMetricsUseCase(mockRepo)
+	useCase := usecases.NewGetMetricsUseCase(mockRepo, nil)
 
 	// Execute the use case
-	result, err := useCase.Execute(context.Background(), lastQuotes)
+	result, err := useCase.Execute(context.Background(), query)
 
 	// Assert results
 	assert.NoError(t, err)
@@ -64,6 +66,7 @@ func TestGetMetricsUseCase_Execute_Success(t *testing.T) {
 
 	assert.Equal(t, 12.50, result.CheapestAndMostExpensive.CheapestShipping)
 	assert.Equal(t, 30.75, result.CheapestAndMostExpensive.MostExpensiveShipping)
+	assert.Equal(t, domain.MetricsWindow{Timezone: "UTC", LastQuotes: 10}, result.Window)
 
 	// Verify expectations were met
 	mockRepo.AssertExpectations(t)
@@ -74,15 +77,15 @@ func TestGetMetricsUseCase_Execute_Error(t *testing.T) {
 	mockRepo := new(mocks.MockMetricsRepository)
 
 	// Setup expectations with an error
-	lastQuotes := 10
+	query := domain.MetricsQuery{LastQuotes: 10}
 	expectedError := errors.New("database error")
-	mockRepo.On("GetMetrics", mock.Anything, lastQuotes).Return(nil, expectedError)
+	mockRepo.On("GetMetrics", mock.Anything, query).Return(nil, expectedError)
 
 	// Create the use case with the mock repository
-	useCase := usecases.NewGetMetricsUseCase(mockRepo)
+	useCase := usecases.NewGetMetricsUseCase(mockRepo, nil)
 
 	// Execute the use case
-	result, err := useCase.Execute(context.Background(), lastQuotes)
+	result, err := useCase.Execute(context.Background(), query)
 
 	// Assert results
 	assert.Error(t, err)
@@ -92,3 +95,99 @@ func TestGetMetricsUseCase_Execute_Error(t *testing.T) {
 	// Verify expectations were met
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetMetricsUseCase_Execute_ResolvesPeriod(t *testing.T) {
+	mockRepo := new(mocks.MockMetricsRepository)
+
+	// The repository receives an absolute range instead of the relative period
+	mockRepo.On("GetMetrics", mock.Anything, mock.MatchedBy(func(q domain.MetricsQuery) bool {
+		return q.From != nil && q.To != nil && q.To.Sub(*q.From) == 24*time.Hour
+	})).Return(&domain.MetricsResponse{CarrierMetrics: []domain.QuoteMetrics{}}, nil)
+
+	useCase := usecases.NewGetMetricsUseCase(mockRepo, nil)
+
+	result, err := useCase.Execute(context.Background(), domain.MetricsQuery{Period: domain.PeriodLast24Hours})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PeriodLast24Hours, result.Window.Period)
+	assert.Equal(t, "UTC", result.Window.Timezone)
+	assert.NotNil(t, result.Window.From)
+	assert.NotNil(t, result.Window.To)
+	mockRepo.AssertExpectations(t)
+}
+
+// fillJSON returns a MockCache fill function that decodes value into dest, as the Redis client would
+func fillJSON(value interface{}) func(dest interface{}) {
+	return func(dest interface{}) {
+		data, _ := json.Marshal(value)
+		json.Unmarshal(data, dest)
+	}
+}
+
+func TestGetMetricsUseCase_Execute_CacheHit(t *testing.T) {
+	mockRepo := new(mocks.MockMetricsRepository)
+	mockCache := new(mocks.MockCache)
+
+	query := domain.MetricsQuery{LastQuotes: 10}
+	cached := &domain.MetricsResponse{
+		CarrierMetrics: []domain.QuoteMetrics{{CarrierName: "EXPRESSO FR", TotalQuotes: 3}},
+		Window:         domain.MetricsWindow{Timezone: "UTC", LastQuotes: 10},
+	}
+
+	mockCache.On("GetCacheJSON", mock.Anything, "metrics:generation", mock.Anything).Return(fillJSON(7), nil)
+	mockCache.On("GetCacheJSON", mock.Anything, "metrics:7:"+query.Fingerprint(), mock.Anything).Return(fillJSON(cached), nil)
+
+	useCase := usecases.NewGetMetricsUseCase(mockRepo, usecases.NewMetricsCache(mockCache, time.Minute))
+
+	result, err := useCase.Execute(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cached, result)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetMetrics", mock.Anything, mock.Anything)
+}
+
+func TestGetMetricsUseCase_Execute_CacheMiss(t *testing.T) {
+	mockRepo := new(mocks.MockMetricsRepository)
+	mockCache := new(mocks.MockCache)
+
+	query := domain.MetricsQuery{LastQuotes: 10}
+	cacheKey := "metrics:0:" + query.Fingerprint()
+
+	// Before the first saved quote there is no generation counter yet
+	mockCache.On("GetCacheJSON", mock.Anything, "metrics:generation", mock.Anything).Return(nil, domain.ErrCacheMiss)
+	mockCache.On("GetCacheJSON", mock.Anything, cacheKey, mock.Anything).Return(nil, domain.ErrCacheMiss)
+	mockRepo.On("GetMetrics", mock.Anything, query).Return(&domain.MetricsResponse{CarrierMetrics: []domain.QuoteMetrics{}}, nil)
+	mockCache.On("SetCacheJSON", mock.Anything, cacheKey, mock.MatchedBy(func(response *domain.MetricsResponse) bool {
+		return response.Window.LastQuotes == 10
+	}), time.Minute).Return(nil)
+
+	useCase := usecases.NewGetMetricsUseCase(mockRepo, usecases.NewMetricsCache(mockCache, time.Minute))
+
+	result, err := useCase.Execute(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, result.Window.LastQuotes)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMetricsUseCase_Execute_CacheError(t *testing.T) {
+	mockRepo := new(mocks.MockMetricsRepository)
+	mockCache := new(mocks.MockCache)
+
+	query := domain.MetricsQuery{}
+
+	// Without a readable generation the response is computed but not cached
+	mockCache.On("GetCacheJSON", mock.Anything, "metrics:generation", mock.Anything).Return(nil, errors.New("connection refused"))
+	mockRepo.On("GetMetrics", mock.Anything, query).Return(&domain.MetricsResponse{CarrierMetrics: []domain.QuoteMetrics{}}, nil)
+
+	useCase := usecases.NewGetMetricsUseCase(mockRepo, usecases.NewMetricsCache(mockCache, time.Minute))
+
+	result, err := useCase.Execute(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "SetCacheJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+type GetQuoteUseCase struct {
+	quoteRepository domain.QuoteRepository
+}
+
+func NewGetQuoteUseCase(quoteRepository domain.QuoteRepository) *GetQuoteUseCase {
+	return &GetQuoteUseCase{
+		quoteRepository: quoteRepository,
+	}
+}
+
+// Execute returns a stored quote, or a not_found error when it does not exist
+func (uc *GetQuoteUseCase) Execute(ctx context.Context, id uint) (*domain.QuoteResponse, error) {
+	return uc.quoteRepository.GetQuote(ctx, id)
+}
@@ -0,0 +1,34 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
+)
+
+func TestGetQuoteUseCase_Execute(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+	stored := &newStoredQuotes(1)[0]
+	mockRepo.On("GetQuote", mock.Anything, uint(1)).Return(stored, nil)
+
+	result, err := usecases.NewGetQuoteUseCase(mockRepo).Execute(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+}
+
+func TestGetQuoteUseCase_ExecuteNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockRepo.On("GetQuote", mock.Anything, uint(42)).Return(nil, domain.NewNotFoundError("quote 42 not found"))
+
+	result, err := usecases.NewGetQuoteUseCase(mockRepo).Execute(context.Background(), 42)
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+}
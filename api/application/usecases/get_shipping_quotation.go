@@ -1,159 +1,124 @@
 package usecases
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"net/http"
-	"os"
-	"strconv"
 
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/circuitbreaker"
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 )
 
 type GetShippingQuotationUseCase struct {
-	quoteRepository domain.QuoteRepository
+	quoteRepository  domain.QuoteRepository
+	shippingProvider domain.ShippingProvider
+	circuitBreaker   *circuitbreaker.CircuitBreaker
+	quoteCache       *QuoteCache
+	metricsCache     *MetricsCache
+	flights          *quoteFlightGroup
 }
 
-func NewGetShippingQuotationUseCase(quoteRepository domain.QuoteRepository) *GetShippingQuotationUseCase {
+// NewGetShippingQuotationUseCase creates the use case.
+// A nil circuitBreaker calls the provider directly and a nil quoteCache disables caching.
+// Saved quotes invalidate metricsCache, when one is configured.
+func NewGetShippingQuotationUseCase(
+	quoteRepository domain.QuoteRepository,
+	shippingProvider domain.ShippingProvider,
+	circuitBreaker *circuitbreaker.CircuitBreaker,
+	quoteCache *QuoteCache,
+	metricsCache *MetricsCache,
+) *GetShippingQuotationUseCase {
 	return &GetShippingQuotationUseCase{
-		quoteRepository: quoteRepository,
+		quoteRepository:  quoteRepository,
+		shippingProvider: shippingProvider,
+		circuitBreaker:   circuitBreaker,
+		quoteCache:       quoteCache,
+		metricsCache:     metricsCache,
+		flights:          newQuoteFlightGroup(),
 	}
 }
 
-// Função auxiliar para obter variáveis de ambiente com valor padrão
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		log.Printf("WARNING: Environment variable %s not found, using default value: %s", key, defaultValue)
-		return defaultValue
-	}
-	return value
-}
-
 func (uc *GetShippingQuotationUseCase) Execute(ctx context.Context, request domain.QuoteRequest) (*domain.QuoteResponse, error) {
-	freteRapidoRequest := prepareFRRequest(request)
+	fingerprint := request.Fingerprint()
 
-	frResponse, err := callFreteRapidoAPI(freteRapidoRequest)
-	if err != nil {
-		return nil, fmt.Errorf("error calling Frete Rápido API: %w", err)
+	if uc.quoteCache != nil {
+		if cached, ok := uc.quoteCache.Get(ctx, fingerprint); ok {
+			cached.CacheHit = true
+			return cached, nil
+		}
 	}
 
-	quoteResponse := transformResponse(frResponse)
-
-	err = uc.quoteRepository.SaveQuote(ctx, quoteResponse)
+	// Identical requests already in flight share one upstream call and one stored quote
+	quoteResponse, _, err := uc.flights.Do(ctx, fingerprint, func(ctx context.Context) (*domain.QuoteResponse, error) {
+		return uc.quoteAndSave(ctx, fingerprint, request)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error saving quote: %w", err)
-	}
-
-	return quoteResponse, nil
-}
-
-func prepareFRRequest(request domain.QuoteRequest) domain.FreteRapidoRequest {
-	frRequest := domain.FreteRapidoRequest{}
-
-	frRequest.Shipper.RegisteredNumber = getEnv("CNPJ", "25438296000158")
-	frRequest.Shipper.Token = getEnv("FRETE_RAPIDO_TOKEN", "1d52a9b6b78cf07b08586152459a5c90")
-	frRequest.Shipper.PlatformCode = getEnv("PLATFORM_CODE", "5AKVkHqCn")
-
-	frRequest.Recipient.Type = 0
-	frRequest.Recipient.Country = "BRA"
-
-	zipcodeInt, _ := strconv.Atoi(request.Recipient.Address.Zipcode)
-	frRequest.Recipient.Zipcode = zipcodeInt
-
-	zipcodeEnvInt, _ := strconv.Atoi(getEnv("ZIPCODE", "29161376"))
-	dispatcher := struct {
-		RegisteredNumber string                     `json:"registered_number"`
-		Zipcode          int                        `json:"zipcode"`
-		Volumes          []domain.FreteRapidoVolume `json:"volumes"`
-	}{
-		RegisteredNumber: getEnv("CNPJ", "25438296000158"),
-		Zipcode:          zipcodeEnvInt,
-		Volumes:          []domain.FreteRapidoVolume{},
-	}
-
-	for _, vol := range request.Volumes {
-		frVolume := domain.FreteRapidoVolume{
-			Amount:        vol.Amount,
-			Category:      strconv.Itoa(vol.Category),
-			Sku:           vol.SKU,
-			Height:        vol.Height,
-			Width:         vol.Width,
-			Length:        vol.Length,
-			UnitaryWeight: vol.UnitaryWeight,
-			UnitaryPrice:  vol.Price,
+		// The caller's own deadline expired while it waited for the shared quote
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			return nil, domain.NewTimeoutError(err)
 		}
-		dispatcher.Volumes = append(dispatcher.Volumes, frVolume)
+		return nil, err
 	}
 
-	frRequest.Dispatchers = append(frRequest.Dispatchers, dispatcher)
-	frRequest.SimulationType = []int{0}
-	frRequest.Returns.Composition = false
-	frRequest.Returns.Volumes = false
-	frRequest.Returns.AppliedRules = false
-
-	jsonData, _ := json.MarshalIndent(frRequest, "", "  ")
-	log.Printf("FreteRapido Request: %s", string(jsonData))
-
-	return frRequest
+	return quoteResponse, nil
 }
 
-func callFreteRapidoAPI(request domain.FreteRapidoRequest) (*domain.FreteRapidoResponse, error) {
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("error marshaling request: %w", err)
-	}
-
-	apiURL := getEnv("FRETE_RAPIDO_API_URL", "https://sp.freterapido.com/api/v3/quote/simulate")
-	log.Printf("Calling FreteRapido API at: %s", apiURL)
-
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
+func (uc *GetShippingQuotationUseCase) quoteAndSave(ctx context.Context, fingerprint string, request domain.QuoteRequest) (*domain.QuoteResponse, error) {
+	quoteResponse, err := uc.quote(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+		// Providers should classify their errors; anything else is treated as an outage
+		if domain.ErrorCodeOf(err) == domain.CodeInternal {
+			err = domain.NewUpstreamUnavailableError(err)
+		}
+		return nil, fmt.Errorf("error calling shipping provider: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	quoteResponse.RecipientZipcode = request.Recipient.Address.Zipcode
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	err = uc.quoteRepository.SaveQuote(ctx, quoteResponse, request)
 	if err != nil {
-		return nil, fmt.Errorf("error executing HTTP request: %w", err)
+		if domain.ErrorCodeOf(err) == domain.CodeInternal {
+			err = domain.NewPersistenceError(err)
+		}
+		return nil, fmt.Errorf("error saving quote: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody := make([]byte, 1024)
-		n, _ := resp.Body.Read(respBody)
-		return nil, fmt.Errorf("received non-200 response status: %d, body: %s", resp.StatusCode, string(respBody[:n]))
+	if uc.metricsCache != nil {
+		uc.metricsCache.Invalidate(ctx)
 	}
 
-	var frResponse domain.FreteRapidoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&frResponse); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	if uc.quoteCache != nil {
+		uc.quoteCache.Set(ctx, fingerprint, quoteResponse)
 	}
 
-	return &frResponse, nil
+	return quoteResponse, nil
 }
 
-func transformResponse(frResponse *domain.FreteRapidoResponse) *domain.QuoteResponse {
-	response := &domain.QuoteResponse{
-		Carriers: []domain.Carrier{},
+// quote calls the shipping provider through the circuit breaker, if one is configured
+func (uc *GetShippingQuotationUseCase) quote(ctx context.Context, request domain.QuoteRequest) (*domain.QuoteResponse, error) {
+	if uc.circuitBreaker == nil {
+		return uc.shippingProvider.Quote(ctx, request)
 	}
 
-	for _, dispatcher := range frResponse.Dispatchers {
-		for _, offer := range dispatcher.Offers {
-			carrier := domain.Carrier{
-				Name:     offer.Carrier.Name,
-				Service:  offer.Service,
-				Deadline: strconv.Itoa(offer.DeliveryTime.Days),
-				Price:    offer.FinalPrice,
-			}
-			response.Carriers = append(response.Carriers, carrier)
+	var quoteResponse *domain.QuoteResponse
+	err := uc.circuitBreaker.Execute(func() error {
+		var err error
+		quoteResponse, err = uc.shippingProvider.Quote(ctx, request)
+		return err
+	}, func(err error) bool {
+		// A client that went away or a request the upstream refused says nothing about its health
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			return false
 		}
+		return !errors.Is(err, domain.ErrUpstreamRejected)
+	})
+
+	var openErr *circuitbreaker.OpenError
+	if errors.As(err, &openErr) {
+		unavailableErr := domain.NewUpstreamUnavailableError(err)
+		unavailableErr.RetryAfter = openErr.RetryAfter
+		return nil, unavailableErr
 	}
 
-	return response
+	return quoteResponse, err
 }
@@ -2,27 +2,22 @@ package usecases_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/circuitbreaker"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
 )
 
-// This is a simplified test focusing on the repository interaction
-// A more comprehensive test would also mock the HTTP client for testing the API call
-func TestGetShippingQuotationUseCase_Execute(t *testing.T) {
-	// Skip this test since we need to mock HTTP client functionality
-	// which would require refactoring the use case to accept a client interface
-	t.Skip("Skipping test that requires HTTP client mocking")
-
-	// Create a mock repository
-	mockRepo := new(mocks.MockQuoteRepository)
-
-	// Setup test request
+func newTestQuoteRequest() domain.QuoteRequest {
 	request := domain.QuoteRequest{}
 	request.Recipient.Address.Zipcode = "01311000"
 
@@ -38,12 +33,36 @@ func TestGetShippingQuotationUseCase_Execute(t *testing.T) {
 	}
 
 	request.Volumes = append(request.Volumes, volume)
+	return request
+}
+
+func newTestQuoteResponse() *domain.QuoteResponse {
+	return &domain.QuoteResponse{
+		Carriers: []domain.Carrier{
+			{
+				Name:     "EXPRESSO FR",
+				Service:  "Rodoviário",
+				Deadline: "3",
+				Price:    17.0,
+			},
+		},
+	}
+}
+
+func TestGetShippingQuotationUseCase_Execute(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+
+	request := newTestQuoteRequest()
+	providerResponse := newTestQuoteResponse()
 
-	// Setup mock for repository save
-	mockRepo.On("SaveQuote", mock.Anything, mock.AnythingOfType("*domain.QuoteResponse")).Return(nil)
+	// Setup expectations
+	mockProvider.On("Quote", mock.Anything, request).Return(providerResponse, nil)
+	mockRepo.On("SaveQuote", mock.Anything, providerResponse, request).Return(nil)
 
-	// Create the use case with the mock repository
-	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo)
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, nil)
 
 	// Execute the use case
 	result, err := useCase.Execute(context.Background(), request)
@@ -51,42 +70,59 @@ func TestGetShippingQuotationUseCase_Execute(t *testing.T) {
 	// Assert results
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
+	assert.Len(t, result.Carriers, 1)
+	assert.Equal(t, "EXPRESSO FR", result.Carriers[0].Name)
+	assert.Equal(t, "01311000", result.RecipientZipcode)
 
 	// Verify expectations were met
+	mockProvider.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
-// Test error handling when saving quote
-func TestGetShippingQuotationUseCase_SaveError(t *testing.T) {
-	// Skip this test since we need to mock HTTP client functionality
-	t.Skip("Skipping test that requires HTTP client mocking")
-
-	// Create a mock repository
+// Test error handling when the shipping provider fails
+func TestGetShippingQuotationUseCase_ProviderError(t *testing.T) {
+	// Create mocks
 	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
 
-	// Setup test request
-	request := domain.QuoteRequest{}
-	request.Recipient.Address.Zipcode = "01311000"
+	request := newTestQuoteRequest()
 
-	volume := domain.Volume{
-		Category:      7,
-		Amount:        1,
-		UnitaryWeight: 5.0,
-		Price:         349.0,
-		SKU:           "abc-teste-123",
-		Height:        0.2,
-		Width:         0.2,
-		Length:        0.2,
-	}
+	// Setup mock for provider with error
+	mockProvider.On("Quote", mock.Anything, request).Return(nil, errors.New("upstream error"))
 
-	request.Volumes = append(request.Volumes, volume)
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, nil)
+
+	// Execute the use case
+	result, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "error calling shipping provider")
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+
+	// The quote must not be saved
+	mockProvider.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SaveQuote", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test error handling when saving quote
+func TestGetShippingQuotationUseCase_SaveError(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+
+	request := newTestQuoteRequest()
+	providerResponse := newTestQuoteResponse()
 
 	// Setup mock for repository save with error
 	expectedError := errors.New("database error")
-	mockRepo.On("SaveQuote", mock.Anything, mock.AnythingOfType("*domain.QuoteResponse")).Return(expectedError)
+	mockProvider.On("Quote", mock.Anything, request).Return(providerResponse, nil)
+	mockRepo.On("SaveQuote", mock.Anything, mock.AnythingOfType("*domain.QuoteResponse"), mock.Anything).Return(expectedError)
 
-	// Create the use case with the mock repository
-	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo)
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, nil)
 
 	// Execute the use case
 	result, err := useCase.Execute(context.Background(), request)
@@ -95,7 +131,308 @@ func TestGetShippingQuotationUseCase_SaveError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "error saving quote")
+	assert.ErrorIs(t, err, domain.ErrPersistenceFailure)
 
 	// Verify expectations were met
+	mockProvider.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
+
+// Test that an open circuit breaker short-circuits the provider call
+func TestGetShippingQuotationUseCase_CircuitOpen(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+
+	request := newTestQuoteRequest()
+
+	// Every call fails, so the breaker trips after MinRequests
+	mockProvider.On("Quote", mock.Anything, request).Return(nil, errors.New("upstream error"))
+
+	breaker := circuitbreaker.NewCircuitBreaker("test", config.CircuitBreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  2,
+		Window:       time.Minute,
+		CoolDown:     time.Minute,
+	})
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, breaker, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := useCase.Execute(context.Background(), request)
+		assert.Error(t, err)
+	}
+
+	// Execute the use case with the breaker open
+	result, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, circuitbreaker.ErrOpen)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+
+	// The provider was only called until the breaker opened
+	mockProvider.AssertNumberOfCalls(t, "Quote", 2)
+	mockRepo.AssertNotCalled(t, "SaveQuote", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test that a cached quote is served without calling the provider or saving a new row
+func TestGetShippingQuotationUseCase_CacheHit(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+	mockCache := new(mocks.MockCache)
+
+	request := newTestQuoteRequest()
+	cacheKey := "quote:" + request.Fingerprint()
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockCache.On("GetCacheJSON", mock.Anything, cacheKey, mock.Anything).Return(func(dest interface{}) {
+		// Fill dest the same way the Redis client would
+		data, _ := json.Marshal(map[string]interface{}{
+			"response":   newTestQuoteResponse(),
+			"expires_at": expiresAt,
+		})
+		json.Unmarshal(data, dest)
+	}, nil)
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, usecases.NewQuoteCache(mockCache, time.Hour), nil)
+
+	// Execute the use case
+	result, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.NoError(t, err)
+	assert.True(t, result.CacheHit)
+	assert.Len(t, result.Carriers, 1)
+	assert.Equal(t, "EXPRESSO FR", result.Carriers[0].Name)
+
+	mockCache.AssertExpectations(t)
+	mockProvider.AssertNotCalled(t, "Quote", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "SaveQuote", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test that a miss calls the provider and caches the result until the offers expire
+func TestGetShippingQuotationUseCase_CacheMiss(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+	mockCache := new(mocks.MockCache)
+
+	request := newTestQuoteRequest()
+	cacheKey := "quote:" + request.Fingerprint()
+
+	providerResponse := newTestQuoteResponse()
+	providerResponse.ExpiresAt = time.Now().Add(30 * time.Minute)
+
+	mockCache.On("GetCacheJSON", mock.Anything, cacheKey, mock.Anything).Return(nil, domain.ErrCacheMiss)
+	mockProvider.On("Quote", mock.Anything, request).Return(providerResponse, nil)
+	mockRepo.On("SaveQuote", mock.Anything, providerResponse, request).Return(nil)
+
+	// The TTL is capped by the configured maximum
+	mockCache.On("SetCacheJSON", mock.Anything, cacheKey, mock.Anything, 10*time.Minute).Return(nil)
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, usecases.NewQuoteCache(mockCache, 10*time.Minute), nil)
+
+	// Execute the use case
+	result, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.NoError(t, err)
+	assert.False(t, result.CacheHit)
+
+	mockCache.AssertExpectations(t)
+	mockProvider.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+// Test that a cache failure degrades to calling the provider
+func TestGetShippingQuotationUseCase_CacheError(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+	mockCache := new(mocks.MockCache)
+
+	request := newTestQuoteRequest()
+	providerResponse := newTestQuoteResponse()
+
+	mockCache.On("GetCacheJSON", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	mockProvider.On("Quote", mock.Anything, request).Return(providerResponse, nil)
+	mockRepo.On("SaveQuote", mock.Anything, providerResponse, request).Return(nil)
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, usecases.NewQuoteCache(mockCache, time.Hour), nil)
+
+	// Execute the use case
+	result, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	mockProvider.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+
+	// Offers without expiration are never cached
+	mockCache.AssertNotCalled(t, "SetCacheJSON", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test that a saved quote starts a new metrics cache generation
+func TestGetShippingQuotationUseCase_InvalidatesMetricsCache(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+	mockCache := new(mocks.MockCache)
+
+	request := newTestQuoteRequest()
+	providerResponse := newTestQuoteResponse()
+
+	mockProvider.On("Quote", mock.Anything, request).Return(providerResponse, nil)
+	mockRepo.On("SaveQuote", mock.Anything, providerResponse, request).Return(nil)
+	mockCache.On("IncrementCache", mock.Anything, "metrics:generation").Return(int64(2), nil)
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, usecases.NewMetricsCache(mockCache, time.Minute))
+
+	// Execute the use case
+	_, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.NoError(t, err)
+	mockCache.AssertExpectations(t)
+}
+
+// Test that a quote that failed to save keeps the cached metrics
+func TestGetShippingQuotationUseCase_SaveErrorKeepsMetricsCache(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+	mockCache := new(mocks.MockCache)
+
+	request := newTestQuoteRequest()
+	providerResponse := newTestQuoteResponse()
+
+	mockProvider.On("Quote", mock.Anything, request).Return(providerResponse, nil)
+	mockRepo.On("SaveQuote", mock.Anything, providerResponse, request).Return(errors.New("database error"))
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, usecases.NewMetricsCache(mockCache, time.Minute))
+
+	// Execute the use case
+	_, err := useCase.Execute(context.Background(), request)
+
+	// Assert results
+	assert.Error(t, err)
+	mockCache.AssertNotCalled(t, "IncrementCache", mock.Anything, mock.Anything)
+}
+
+// Test that concurrent identical requests share one provider call and one stored quote
+func TestGetShippingQuotationUseCase_CoalescesIdenticalRequests(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+
+	request := newTestQuoteRequest()
+	release := make(chan struct{})
+
+	mockProvider.On("Quote", mock.Anything, request).Run(func(args mock.Arguments) {
+		<-release
+	}).Return(newTestQuoteResponse(), nil).Once()
+	mockRepo.On("SaveQuote", mock.Anything, mock.AnythingOfType("*domain.QuoteResponse"), mock.Anything).Return(nil).Once()
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, nil)
+
+	const callers = 5
+	var joined sync.WaitGroup
+	joined.Add(callers - 1)
+	usecases.SetQuoteFlightJoinHook(useCase, joined.Done)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := useCase.Execute(context.Background(), request)
+			assert.NoError(t, err)
+			assert.Len(t, result.Carriers, 1)
+		}()
+	}
+
+	// Every request joins the in-flight quote before it completes
+	joined.Wait()
+	close(release)
+	wg.Wait()
+
+	mockProvider.AssertNumberOfCalls(t, "Quote", 1)
+	mockRepo.AssertNumberOfCalls(t, "SaveQuote", 1)
+}
+
+func TestGetShippingQuotationUseCase_FollowerTimeout(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+
+	request := newTestQuoteRequest()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	mockProvider.On("Quote", mock.Anything, request).Run(func(args mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(newTestQuoteResponse(), nil).Once()
+	mockRepo.On("SaveQuote", mock.Anything, mock.AnythingOfType("*domain.QuoteResponse"), mock.Anything).Return(nil).Once()
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, nil, nil, nil)
+
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := useCase.Execute(context.Background(), request)
+		leaderDone <- err
+	}()
+	<-started
+
+	// The follower's deadline expires before the shared quote is ready
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result, err := useCase.Execute(ctx, request)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domain.ErrTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, <-leaderDone)
+}
+
+// Test that requests refused by the upstream do not trip the circuit breaker
+func TestGetShippingQuotationUseCase_RejectedDoesNotTripBreaker(t *testing.T) {
+	// Create mocks
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockProvider := new(mocks.MockShippingProvider)
+
+	request := newTestQuoteRequest()
+	mockProvider.On("Quote", mock.Anything, request).Return(nil, domain.NewUpstreamRejectedError(errors.New("status 400")))
+
+	breaker := circuitbreaker.NewCircuitBreaker("test", config.CircuitBreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  1,
+		Window:       time.Minute,
+		CoolDown:     time.Minute,
+	})
+
+	// Create the use case with the mocks
+	useCase := usecases.NewGetShippingQuotationUseCase(mockRepo, mockProvider, breaker, nil, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := useCase.Execute(context.Background(), request)
+		assert.ErrorIs(t, err, domain.ErrUpstreamRejected)
+	}
+
+	assert.Equal(t, circuitbreaker.StateClosed, breaker.State())
+	mockProvider.AssertNumberOfCalls(t, "Quote", 3)
+}
@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+type ListQuotesUseCase struct {
+	quoteRepository domain.QuoteRepository
+}
+
+func NewListQuotesUseCase(quoteRepository domain.QuoteRepository) *ListQuotesUseCase {
+	return &ListQuotesUseCase{
+		quoteRepository: quoteRepository,
+	}
+}
+
+// Execute returns one page of stored quotes, newest first, with the cursor for the next page
+func (uc *ListQuotesUseCase) Execute(ctx context.Context, filter domain.QuoteFilter) (*domain.QuotePage, error) {
+	// Ask for one extra quote to know whether there is a next page
+	pageSize := filter.Limit
+	filter.Limit++
+
+	quotes, err := uc.quoteRepository.ListQuotes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.QuotePage{Quotes: quotes}
+	if len(quotes) > pageSize {
+		page.Quotes = quotes[:pageSize]
+		last := page.Quotes[pageSize-1]
+		page.NextCursor = domain.QuoteCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	if page.Quotes == nil {
+		page.Quotes = []domain.QuoteResponse{}
+	}
+
+	return page, nil
+}
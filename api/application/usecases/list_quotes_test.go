@@ -0,0 +1,81 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
+	"gorm.io/gorm"
+)
+
+func newStoredQuotes(count int) []domain.QuoteResponse {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	quotes := make([]domain.QuoteResponse, 0, count)
+	for i := 0; i < count; i++ {
+		quotes = append(quotes, domain.QuoteResponse{
+			Model:    gorm.Model{ID: uint(count - i), CreatedAt: now.Add(-time.Duration(i) * time.Minute)},
+			Carriers: []domain.Carrier{{Name: "EXPRESSO FR", Price: 17.0}},
+		})
+	}
+	return quotes
+}
+
+func TestListQuotesUseCase_Execute(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+
+	// The repository is asked for one extra quote to detect the next page
+	stored := newStoredQuotes(3)
+	mockRepo.On("ListQuotes", mock.Anything, domain.QuoteFilter{CarrierName: "Correios", Limit: 3}).Return(stored, nil)
+
+	useCase := usecases.NewListQuotesUseCase(mockRepo)
+
+	page, err := useCase.Execute(context.Background(), domain.QuoteFilter{CarrierName: "Correios", Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Quotes, 2)
+
+	cursor, err := domain.DecodeQuoteCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, stored[1].ID, cursor.ID)
+	assert.True(t, stored[1].CreatedAt.Equal(cursor.CreatedAt))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListQuotesUseCase_ExecuteLastPage(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockRepo.On("ListQuotes", mock.Anything, domain.QuoteFilter{Limit: 3}).Return(newStoredQuotes(2), nil)
+
+	page, err := usecases.NewListQuotesUseCase(mockRepo).Execute(context.Background(), domain.QuoteFilter{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Quotes, 2)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestListQuotesUseCase_ExecuteEmpty(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockRepo.On("ListQuotes", mock.Anything, mock.Anything).Return(nil, nil)
+
+	page, err := usecases.NewListQuotesUseCase(mockRepo).Execute(context.Background(), domain.QuoteFilter{Limit: 20})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, page.Quotes)
+	assert.Empty(t, page.Quotes)
+}
+
+func TestListQuotesUseCase_ExecuteError(t *testing.T) {
+	mockRepo := new(mocks.MockQuoteRepository)
+	mockRepo.On("ListQuotes", mock.Anything, mock.Anything).Return(nil, domain.NewPersistenceError(errors.New("connection refused")))
+
+	page, err := usecases.NewListQuotesUseCase(mockRepo).Execute(context.Background(), domain.QuoteFilter{Limit: 20})
+
+	assert.Nil(t, page)
+	assert.True(t, errors.Is(err, domain.ErrPersistenceFailure))
+}
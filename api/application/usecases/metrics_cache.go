@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// metricsGenerationKey holds a counter incremented on every saved quote. Cached metrics
+// are keyed by the generation they were computed in, so bumping it invalidates all of them.
+const metricsGenerationKey = "metrics:generation"
+
+// MetricsCache stores metrics responses keyed by the query fingerprint until a new quote is saved
+type MetricsCache struct {
+	cache domain.Cache
+	ttl   time.Duration
+}
+
+// NewMetricsCache creates the cache. The ttl bounds how stale relative periods, which move with
+// the clock, can get while no quote is saved.
+func NewMetricsCache(cache domain.Cache, ttl time.Duration) *MetricsCache {
+	return &MetricsCache{
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func metricsCacheKey(generation int64, fingerprint string) string {
+	return "metrics:" + strconv.FormatInt(generation, 10) + ":" + fingerprint
+}
+
+// Get returns the cached response for the query in the current generation, if any, and the key
+// under which a freshly computed response must be stored. The key is empty when the generation
+// could not be read; cache failures are logged and reported as a miss so they never fail a request.
+func (c *MetricsCache) Get(ctx context.Context, query domain.MetricsQuery) (*domain.MetricsResponse, string, bool) {
+	var generation int64
+	if err := c.cache.GetCacheJSON(ctx, metricsGenerationKey, &generation); err != nil && !errors.Is(err, domain.ErrCacheMiss) {
+		log.Printf("WARNING: failed to read metrics cache generation: %v", err)
+		return nil, "", false
+	}
+
+	key := metricsCacheKey(generation, query.Fingerprint())
+
+	var response domain.MetricsResponse
+	if err := c.cache.GetCacheJSON(ctx, key, &response); err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			log.Printf("WARNING: failed to read metrics cache: %v", err)
+		}
+		return nil, key, false
+	}
+
+	return &response, key, true
+}
+
+// Set caches the response under the key returned by Get. A response computed while a quote was
+// being saved lands in a generation that is already stale, so it is never served.
+func (c *MetricsCache) Set(ctx context.Context, key string, response *domain.MetricsResponse) {
+	if key == "" {
+		return
+	}
+
+	if err := c.cache.SetCacheJSON(ctx, key, response, c.ttl); err != nil {
+		log.Printf("WARNING: failed to write metrics cache: %v", err)
+	}
+}
+
+// Invalidate starts a new generation, so every cached response is recomputed on its next request.
+// Old entries are left to expire with their TTL.
+func (c *MetricsCache) Invalidate(ctx context.Context) {
+	if _, err := c.cache.IncrementCache(ctx, metricsGenerationKey); err != nil {
+		log.Printf("WARNING: failed to invalidate metrics cache: %v", err)
+	}
+}
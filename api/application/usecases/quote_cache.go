@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// QuoteCache stores quote responses keyed by the request fingerprint until the upstream offers expire
+type QuoteCache struct {
+	cache  domain.Cache
+	maxTTL time.Duration
+	now    func() time.Time
+}
+
+type cachedQuote struct {
+	Response  *domain.QuoteResponse `json:"response"`
+	ExpiresAt time.Time             `json:"expires_at"`
+}
+
+func NewQuoteCache(cache domain.Cache, maxTTL time.Duration) *QuoteCache {
+	return &QuoteCache{
+		cache:  cache,
+		maxTTL: maxTTL,
+		now:    time.Now,
+	}
+}
+
+func quoteCacheKey(fingerprint string) string {
+	return "quote:" + fingerprint
+}
+
+// Get returns the cached response for the fingerprint, if it exists and has not expired.
+// Cache failures are logged and reported as a miss so they never fail a quote.
+func (c *QuoteCache) Get(ctx context.Context, fingerprint string) (*domain.QuoteResponse, bool) {
+	var entry cachedQuote
+	if err := c.cache.GetCacheJSON(ctx, quoteCacheKey(fingerprint), &entry); err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			log.Printf("WARNING: failed to read quote cache: %v", err)
+		}
+		return nil, false
+	}
+
+	if entry.Response == nil || !c.now().Before(entry.ExpiresAt) {
+		return nil, false
+	}
+
+	entry.Response.ExpiresAt = entry.ExpiresAt
+	return entry.Response, true
+}
+
+// Set caches the response until its earliest offer expires, capped by maxTTL
+func (c *QuoteCache) Set(ctx context.Context, fingerprint string, response *domain.QuoteResponse) {
+	if response.ExpiresAt.IsZero() {
+		return
+	}
+
+	ttl := response.ExpiresAt.Sub(c.now())
+	if c.maxTTL > 0 && ttl > c.maxTTL {
+		ttl = c.maxTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	entry := cachedQuote{Response: response, ExpiresAt: response.ExpiresAt}
+	if err := c.cache.SetCacheJSON(ctx, quoteCacheKey(fingerprint), entry, ttl); err != nil {
+		log.Printf("WARNING: failed to write quote cache: %v", err)
+	}
+}
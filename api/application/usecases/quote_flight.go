@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// quoteFlight is a quote being computed on behalf of every caller with the same fingerprint
+type quoteFlight struct {
+	done     chan struct{}
+	response *domain.QuoteResponse
+	err      error
+}
+
+// quoteFlightGroup coalesces concurrent identical quote requests into a single upstream call.
+// The shared call runs detached from any single caller's cancellation, while each caller
+// still stops waiting as soon as its own context is done.
+type quoteFlightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*quoteFlight
+	// joined, when set, is called each time a caller joins a flight started by another request
+	joined func()
+}
+
+func newQuoteFlightGroup() *quoteFlightGroup {
+	return &quoteFlightGroup{
+		flights: make(map[string]*quoteFlight),
+	}
+}
+
+// Do runs fn once per in-flight key and hands its result to every caller waiting on that key.
+// shared reports whether the caller joined a flight started by another request.
+func (g *quoteFlightGroup) Do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) (*domain.QuoteResponse, error),
+) (response *domain.QuoteResponse, shared bool, err error) {
+	g.mu.Lock()
+	flight, shared := g.flights[key]
+	if !shared {
+		flight = &quoteFlight{done: make(chan struct{})}
+		g.flights[key] = flight
+
+		go func() {
+			defer func() {
+				// A panic fails every caller of this flight instead of crashing the process
+				if r := recover(); r != nil {
+					log.Printf("ERROR: quote flight panicked: %v\n%s", r, debug.Stack())
+					flight.response, flight.err = nil, fmt.Errorf("quote flight panicked: %v", r)
+				}
+				g.mu.Lock()
+				delete(g.flights, key)
+				g.mu.Unlock()
+				close(flight.done)
+			}()
+			flight.response, flight.err = fn(context.WithoutCancel(ctx))
+		}()
+	}
+	g.mu.Unlock()
+
+	if shared && g.joined != nil {
+		g.joined()
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, shared, ctx.Err()
+	case <-flight.done:
+	}
+
+	if flight.err != nil {
+		return nil, shared, flight.err
+	}
+
+	// Every caller gets its own copy so per-request fields can be set safely
+	responseCopy := *flight.response
+	responseCopy.Carriers = append(domain.CarriersJSON(nil), flight.response.Carriers...)
+	return &responseCopy, shared, nil
+}
@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestQuoteFlightGroup_CoalescesConcurrentCalls(t *testing.T) {
+	group := newQuoteFlightGroup()
+
+	var calls int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (*domain.QuoteResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &domain.QuoteResponse{Carriers: []domain.Carrier{{Name: "EXPRESSO FR", Price: 17.0}}}, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	var sharedCount int32
+	results := make([]*domain.QuoteResponse, callers)
+
+	var joined sync.WaitGroup
+	joined.Add(callers - 1)
+	group.joined = joined.Done
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, shared, err := group.Do(context.Background(), "key", fn)
+			assert.NoError(t, err)
+			if shared {
+				atomic.AddInt32(&sharedCount, 1)
+			}
+			results[i] = response
+		}(i)
+	}
+
+	// Every caller joins the flight before it completes
+	joined.Wait()
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(callers-1), atomic.LoadInt32(&sharedCount))
+
+	// Each caller gets its own copy of the response
+	for i := 1; i < callers; i++ {
+		assert.Equal(t, results[0].Carriers, results[i].Carriers)
+		assert.NotSame(t, results[0], results[i])
+	}
+}
+
+func TestQuoteFlightGroup_FollowerCancellation(t *testing.T) {
+	group := newQuoteFlightGroup()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (*domain.QuoteResponse, error) {
+		close(started)
+		<-release
+		return &domain.QuoteResponse{}, ctx.Err()
+	}
+
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, _, err := group.Do(context.Background(), "key", fn)
+		leaderDone <- err
+	}()
+	<-started
+
+	// The follower gives up on its own deadline without affecting the leader
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	response, shared, err := group.Do(ctx, "key", fn)
+	assert.True(t, shared)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, <-leaderDone)
+}
+
+func TestQuoteFlightGroup_LeaderCancellationDoesNotAbortSharedCall(t *testing.T) {
+	group := newQuoteFlightGroup()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (*domain.QuoteResponse, error) {
+		close(started)
+		<-release
+		return &domain.QuoteResponse{}, ctx.Err()
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, _, err := group.Do(leaderCtx, "key", fn)
+		leaderDone <- err
+	}()
+	<-started
+
+	joined := make(chan struct{})
+	group.joined = func() { close(joined) }
+
+	followerDone := make(chan error, 1)
+	go func() {
+		_, _, err := group.Do(context.Background(), "key", fn)
+		followerDone <- err
+	}()
+
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderDone, context.Canceled)
+
+	// Once the follower has joined, the shared call keeps running for it
+	<-joined
+	close(release)
+	assert.NoError(t, <-followerDone)
+}
+
+func TestQuoteFlightGroup_ErrorsAreSharedAndNotRemembered(t *testing.T) {
+	group := newQuoteFlightGroup()
+	expectedError := errors.New("upstream error")
+
+	_, _, err := group.Do(context.Background(), "key", func(ctx context.Context) (*domain.QuoteResponse, error) {
+		return nil, expectedError
+	})
+	assert.Equal(t, expectedError, err)
+
+	// Once the flight lands, the next call starts a new one
+	response, shared, err := group.Do(context.Background(), "key", func(ctx context.Context) (*domain.QuoteResponse, error) {
+		return &domain.QuoteResponse{}, nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, shared)
+	assert.NotNil(t, response)
+}
+
+func TestQuoteFlightGroup_PanicFailsCallers(t *testing.T) {
+	group := newQuoteFlightGroup()
+
+	response, _, err := group.Do(context.Background(), "key", func(ctx context.Context) (*domain.QuoteResponse, error) {
+		panic("mapping bug")
+	})
+	assert.Nil(t, response)
+	assert.ErrorContains(t, err, "mapping bug")
+
+	// The flight is released, so the next call starts a new one
+	_, shared, err := group.Do(context.Background(), "key", func(ctx context.Context) (*domain.QuoteResponse, error) {
+		return &domain.QuoteResponse{}, nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, shared)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+const aggregatesUsage = "usage: api aggregates rebuild"
+
+// runAggregatesCommand handles "api aggregates rebuild"
+func runAggregatesCommand(db *gorm.DB, args []string) error {
+	if len(args) == 0 || args[0] != "rebuild" {
+		return errors.New(aggregatesUsage)
+	}
+
+	if err := database.RebuildCarrierAggregates(context.Background(), db); err != nil {
+		return err
+	}
+	fmt.Println("Rebuilt carrier aggregates from quote_offers")
+	return nil
+}
@@ -5,10 +5,16 @@ import (
 	"log"
 	"os"
 
+	// Embeds the time zone database so metrics time zones work on images without it
+	_ "time/tzdata"
+
 	"github.com/joho/godotenv"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/circuitbreaker"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
-	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/cache/redis"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/database"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/freterapido"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/interfaces/routers"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -52,8 +58,24 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Run migrations
-	err = db.AutoMigrate(&domain.QuoteResponse{})
+	// "api migrate <up|down|status>" manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(db, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// "api aggregates rebuild" recomputes the running metrics totals and exits
+	if len(os.Args) > 1 && os.Args[1] == "aggregates" {
+		if err := runAggregatesCommand(db, os.Args[2:]); err != nil {
+			log.Fatalf("Aggregates rebuild failed: %v", err)
+		}
+		return
+	}
+
+	// Apply pending migrations
+	err = database.RunMigrations(db)
 	if err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -62,11 +84,55 @@ func main() {
 	quoteRepository := database.NewQuoteRepository(db)
 	metricsRepository := database.NewMetricsRepository(db)
 
-	// Create use cases
-	getShippingQuotationUseCase := usecases.NewGetShippingQuotationUseCase(quoteRepository)
-	getMetricsUseCase := usecases.NewGetMetricsUseCase(metricsRepository)
+	// Create shipping providers
+	freteRapidoConfig, err := config.LoadFreteRapidoConfig()
+	if err != nil {
+		log.Fatalf("Invalid Frete Rápido configuration: %v", err)
+	}
+	shippingProvider := freterapido.NewClient(freteRapidoConfig, nil)
 
-	router := routers.SetupRouter(getShippingQuotationUseCase, getMetricsUseCase)
+	circuitBreakerConfig, err := config.LoadCircuitBreakerConfig()
+	if err != nil {
+		log.Fatalf("Invalid circuit breaker configuration: %v", err)
+	}
+	freteRapidoBreaker := circuitbreaker.NewCircuitBreaker("frete_rapido", circuitBreakerConfig)
+
+	// Create quote and metrics caches (optional: the API keeps working without Redis)
+	quoteCacheConfig, err := config.LoadQuoteCacheConfig()
+	if err != nil {
+		log.Fatalf("Invalid quote cache configuration: %v", err)
+	}
+	metricsCacheConfig, err := config.LoadMetricsCacheConfig()
+	if err != nil {
+		log.Fatalf("Invalid metrics cache configuration: %v", err)
+	}
+
+	var quoteCache *usecases.QuoteCache
+	var metricsCache *usecases.MetricsCache
+	if quoteCacheConfig.Enabled || metricsCacheConfig.Enabled {
+		redisClient, err := redis.NewRedisClient(quoteCacheConfig.Addr, quoteCacheConfig.Password, quoteCacheConfig.DB)
+		if err != nil {
+			log.Printf("Warning: quote and metrics caches disabled: %v", err)
+		} else {
+			defer redisClient.Close()
+			if quoteCacheConfig.Enabled {
+				quoteCache = usecases.NewQuoteCache(redisClient, quoteCacheConfig.MaxTTL)
+			}
+			if metricsCacheConfig.Enabled {
+				metricsCache = usecases.NewMetricsCache(redisClient, metricsCacheConfig.TTL)
+			}
+		}
+	}
+
+	// Create use cases
+	getShippingQuotationUseCase := usecases.NewGetShippingQuotationUseCase(quoteRepository, shippingProvider, freteRapidoBreaker, quoteCache, metricsCache)
+	getMetricsUseCase := usecases.NewGetMetricsUseCase(metricsRepository, metricsCache)
+	getMetricsSeriesUseCase := usecases.NewGetMetricsSeriesUseCase(metricsRepository)
+	listQuotesUseCase := usecases.NewListQuotesUseCase(quoteRepository)
+	getQuoteUseCase := usecases.NewGetQuoteUseCase(quoteRepository)
+	exportQuotesUseCase := usecases.NewExportQuotesUseCase(quoteRepository)
+
+	router := routers.SetupRouter(getShippingQuotationUseCase, getMetricsUseCase, getMetricsSeriesUseCase, listQuotesUseCase, getQuoteUseCase, exportQuotesUseCase, freteRapidoBreaker)
 
 	port := getEnv("PORT", "3000")
 
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +22,57 @@ type Config struct {
 	RedisDB       int
 }
 
+// FreteRapidoConfig holds the credentials and the retry policy used to call the Frete Rápido API
+type FreteRapidoConfig struct {
+	APIURL            string
+	Token             string
+	RegisteredNumber  string
+	PlatformCode      string
+	DispatcherZipcode string
+
+	// Timeout is the overall deadline for a quote, including every retry
+	Timeout time.Duration
+	// AttemptTimeout is the deadline for a single HTTP attempt
+	AttemptTimeout time.Duration
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BackoffInitial is the base delay of the exponential backoff
+	BackoffInitial time.Duration
+	// BackoffMax caps the delay between two attempts
+	BackoffMax time.Duration
+}
+
+// CircuitBreakerConfig controls when the breaker around the shipping provider trips and recovers
+type CircuitBreakerConfig struct {
+	// FailureRatio is the share of failed calls in a window that opens the breaker
+	FailureRatio float64
+	// MinRequests is the number of calls in a window before the ratio is evaluated
+	MinRequests int
+	// Window is how long failures are counted before the counters are reset
+	Window time.Duration
+	// CoolDown is how long the breaker stays open before allowing probes
+	CoolDown time.Duration
+	// HalfOpenMaxRequests is the number of concurrent probes allowed while half-open
+	HalfOpenMaxRequests int
+}
+
+// QuoteCacheConfig holds the Redis connection used to cache quotes
+type QuoteCacheConfig struct {
+	Enabled  bool
+	Addr     string
+	Password string
+	DB       int
+	// MaxTTL caps how long a quote is cached, even if its offers expire later
+	MaxTTL time.Duration
+}
+
+// MetricsCacheConfig controls the cache of metrics responses, which shares the quote cache Redis connection
+type MetricsCacheConfig struct {
+	Enabled bool
+	// TTL bounds how long a response is cached while no quote is saved, since relative periods move with the clock
+	TTL time.Duration
+}
+
 var Settings Config
 
 func LoadConfig(envFile string) error {
@@ -61,3 +114,157 @@ func LoadConfig(envFile string) error {
 
 	return nil
 }
+
+// LoadFreteRapidoConfig reads the Frete Rápido settings from the environment,
+// falling back to the sandbox credentials and a conservative retry policy
+func LoadFreteRapidoConfig() (FreteRapidoConfig, error) {
+	cfg := FreteRapidoConfig{
+		APIURL:            getEnv("FRETE_RAPIDO_API_URL", "https://sp.freterapido.com/api/v3/quote/simulate"),
+		Token:             getEnv("FRETE_RAPIDO_TOKEN", "1d52a9b6b78cf07b08586152459a5c90"),
+		RegisteredNumber:  getEnv("CNPJ", "25438296000158"),
+		PlatformCode:      getEnv("PLATFORM_CODE", "5AKVkHqCn"),
+		DispatcherZipcode: getEnv("ZIPCODE", "29161376"),
+	}
+
+	var err error
+	if cfg.Timeout, err = getDurationEnv("FRETE_RAPIDO_TIMEOUT", 10*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.AttemptTimeout, err = getDurationEnv("FRETE_RAPIDO_ATTEMPT_TIMEOUT", 4*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxRetries, err = getIntEnv("FRETE_RAPIDO_MAX_RETRIES", 2); err != nil {
+		return cfg, err
+	}
+	if cfg.BackoffInitial, err = getDurationEnv("FRETE_RAPIDO_BACKOFF_INITIAL", 200*time.Millisecond); err != nil {
+		return cfg, err
+	}
+	if cfg.BackoffMax, err = getDurationEnv("FRETE_RAPIDO_BACKOFF_MAX", 2*time.Second); err != nil {
+		return cfg, err
+	}
+
+	if cfg.MaxRetries < 0 {
+		return cfg, fmt.Errorf("FRETE_RAPIDO_MAX_RETRIES must not be negative")
+	}
+	if cfg.BackoffMax < cfg.BackoffInitial {
+		return cfg, fmt.Errorf("FRETE_RAPIDO_BACKOFF_MAX must be greater than or equal to FRETE_RAPIDO_BACKOFF_INITIAL")
+	}
+
+	return cfg, nil
+}
+
+// LoadCircuitBreakerConfig reads the circuit breaker settings from the environment
+func LoadCircuitBreakerConfig() (CircuitBreakerConfig, error) {
+	cfg := CircuitBreakerConfig{}
+
+	var err error
+	if cfg.FailureRatio, err = getFloatEnv("CIRCUIT_BREAKER_FAILURE_RATIO", 0.5); err != nil {
+		return cfg, err
+	}
+	if cfg.MinRequests, err = getIntEnv("CIRCUIT_BREAKER_MIN_REQUESTS", 10); err != nil {
+		return cfg, err
+	}
+	if cfg.Window, err = getDurationEnv("CIRCUIT_BREAKER_WINDOW", time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.CoolDown, err = getDurationEnv("CIRCUIT_BREAKER_COOLDOWN", 30*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.HalfOpenMaxRequests, err = getIntEnv("CIRCUIT_BREAKER_HALF_OPEN_MAX_REQUESTS", 1); err != nil {
+		return cfg, err
+	}
+
+	if cfg.FailureRatio <= 0 || cfg.FailureRatio > 1 {
+		return cfg, fmt.Errorf("CIRCUIT_BREAKER_FAILURE_RATIO must be in (0, 1]")
+	}
+	if cfg.MinRequests < 1 {
+		return cfg, fmt.Errorf("CIRCUIT_BREAKER_MIN_REQUESTS must be at least 1")
+	}
+	if cfg.HalfOpenMaxRequests < 1 {
+		return cfg, fmt.Errorf("CIRCUIT_BREAKER_HALF_OPEN_MAX_REQUESTS must be at least 1")
+	}
+
+	return cfg, nil
+}
+
+// LoadQuoteCacheConfig reads the quote cache settings from the environment
+func LoadQuoteCacheConfig() (QuoteCacheConfig, error) {
+	cfg := QuoteCacheConfig{
+		Enabled:  getEnv("QUOTE_CACHE_ENABLED", "true") == "true",
+		Addr:     getEnv("REDIS_HOST", "localhost") + ":" + getEnv("REDIS_PORT", "6379"),
+		Password: strings.TrimSpace(os.Getenv("REDIS_PASSWORD")),
+	}
+
+	var err error
+	if cfg.DB, err = getIntEnv("REDIS_DB", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxTTL, err = getDurationEnv("QUOTE_CACHE_MAX_TTL", 10*time.Minute); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// LoadMetricsCacheConfig reads the metrics cache settings from the environment
+func LoadMetricsCacheConfig() (MetricsCacheConfig, error) {
+	cfg := MetricsCacheConfig{
+		Enabled: getEnv("METRICS_CACHE_ENABLED", "true") == "true",
+	}
+
+	var err error
+	if cfg.TTL, err = getDurationEnv("METRICS_CACHE_TTL", time.Minute); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// Função auxiliar para obter variáveis de ambiente com valor padrão
+func getEnv(key, defaultValue string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		log.Printf("WARNING: Environment variable %s not found, using default value: %s", key, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration for %s: %w", key, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration", key)
+	}
+	return duration, nil
+}
+
+func getIntEnv(key string, defaultValue int) (int, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer for %s: %w", key, err)
+	}
+	return number, nil
+}
+
+func getFloatEnv(key string, defaultValue float64) (float64, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number for %s: %w", key, err)
+	}
+	return number, nil
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// CarrierAggregate acumula as estatísticas de todas as ofertas de uma transportadora,
+// atualizadas a cada cotação gravada para que as métricas sem janela não releiam as ofertas
+type CarrierAggregate struct {
+	// Nome da transportadora
+	Carrier string `gorm:"primaryKey"`
+	// Cotações em que a transportadora teve ao menos uma oferta
+	QuoteCount int `gorm:"not null;default:0"`
+	// Quantidade de ofertas
+	OfferCount int `gorm:"not null;default:0"`
+	// Soma dos preços
+	TotalPrice float64 `gorm:"not null;default:0"`
+	// Soma dos quadrados dos preços, para o desvio padrão
+	TotalPriceSquares float64 `gorm:"not null;default:0"`
+	MinPrice          float64 `gorm:"not null"`
+	MaxPrice          float64 `gorm:"not null"`
+	// Soma dos prazos de entrega, em dias
+	TotalDeadlineDays int `gorm:"not null;default:0"`
+	MinDeadlineDays   int `gorm:"not null"`
+	MaxDeadlineDays   int `gorm:"not null"`
+	// Cotações em que a transportadora teve a oferta mais barata (empates contam para todas)
+	CheapestCount int `gorm:"not null;default:0"`
+	UpdatedAt     time.Time
+}
+
+func (CarrierAggregate) TableName() string {
+	return "carrier_aggregates"
+}
+
+// CarrierAggregates resume as ofertas da cotação por transportadora, em ordem alfabética,
+// no formato somado aos agregados acumulados
+func (q QuoteResponse) CarrierAggregates() []CarrierAggregate {
+	if len(q.Carriers) == 0 {
+		return []CarrierAggregate{}
+	}
+
+	cheapest := q.Carriers[0].Price
+	for _, carrier := range q.Carriers {
+		cheapest = math.Min(cheapest, carrier.Price)
+	}
+
+	byCarrier := make(map[string]*CarrierAggregate)
+	for _, carrier := range q.Carriers {
+		aggregate, ok := byCarrier[carrier.Name]
+		if !ok {
+			aggregate = &CarrierAggregate{
+				Carrier:         carrier.Name,
+				QuoteCount:      1,
+				MinPrice:        carrier.Price,
+				MaxPrice:        carrier.Price,
+				MinDeadlineDays: carrier.DeadlineDays,
+				MaxDeadlineDays: carrier.DeadlineDays,
+			}
+			byCarrier[carrier.Name] = aggregate
+		}
+
+		aggregate.OfferCount++
+		aggregate.TotalPrice += carrier.Price
+		aggregate.TotalPriceSquares += carrier.Price * carrier.Price
+		aggregate.MinPrice = math.Min(aggregate.MinPrice, carrier.Price)
+		aggregate.MaxPrice = math.Max(aggregate.MaxPrice, carrier.Price)
+		aggregate.TotalDeadlineDays += carrier.DeadlineDays
+		aggregate.MinDeadlineDays = min(aggregate.MinDeadlineDays, carrier.DeadlineDays)
+		aggregate.MaxDeadlineDays = max(aggregate.MaxDeadlineDays, carrier.DeadlineDays)
+		if carrier.Price == cheapest {
+			aggregate.CheapestCount = 1
+		}
+	}
+
+	aggregates := make([]CarrierAggregate, 0, len(byCarrier))
+	for _, aggregate := range byCarrier {
+		aggregates = append(aggregates, *aggregate)
+	}
+	sort.Slice(aggregates, func(i, j int) bool {
+		return aggregates[i].Carrier < aggregates[j].Carrier
+	})
+	return aggregates
+}
+
+// Metrics converte os agregados nas métricas da transportadora. Percentis não podem ser
+// acumulados e ficam ausentes.
+func (a CarrierAggregate) Metrics() QuoteMetrics {
+	metrics := QuoteMetrics{
+		CarrierName:        a.Carrier,
+		TotalQuotes:        a.OfferCount,
+		TotalShippingPrice: a.TotalPrice,
+		MinShippingPrice:   a.MinPrice,
+		MaxShippingPrice:   a.MaxPrice,
+		MinDeliveryDays:    a.MinDeadlineDays,
+		MaxDeliveryDays:    a.MaxDeadlineDays,
+		CheapestCount:      a.CheapestCount,
+	}
+
+	if a.OfferCount > 0 {
+		count := float64(a.OfferCount)
+		metrics.AverageShippingPrice = a.TotalPrice / count
+		// Population variance, clamped because rounding can make it slightly negative
+		variance := a.TotalPriceSquares/count - metrics.AverageShippingPrice*metrics.AverageShippingPrice
+		metrics.StddevShippingPrice = math.Sqrt(math.Max(variance, 0))
+		metrics.AverageDeliveryDays = float64(a.TotalDeadlineDays) / count
+	}
+	if a.QuoteCount > 0 {
+		metrics.CheapestShare = float64(a.CheapestCount) / float64(a.QuoteCount)
+	}
+
+	return metrics
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestQuoteResponse_CarrierAggregates(t *testing.T) {
+	quote := domain.QuoteResponse{
+		Carriers: []domain.Carrier{
+			{Name: "EXPRESSO FR", Price: 20.0, DeadlineDays: 4},
+			{Name: "Correios", Price: 15.0, DeadlineDays: 2},
+			{Name: "EXPRESSO FR", Price: 15.0, DeadlineDays: 3},
+			{Name: "BTU", Price: 30.0, DeadlineDays: 1},
+		},
+	}
+
+	aggregates := quote.CarrierAggregates()
+
+	// Tied cheapest offers count for every carrier, and only once per quote
+	assert.Equal(t, []domain.CarrierAggregate{
+		{Carrier: "BTU", QuoteCount: 1, OfferCount: 1, TotalPrice: 30.0, TotalPriceSquares: 900.0, MinPrice: 30.0, MaxPrice: 30.0,
+			TotalDeadlineDays: 1, MinDeadlineDays: 1, MaxDeadlineDays: 1},
+		{Carrier: "Correios", QuoteCount: 1, OfferCount: 1, TotalPrice: 15.0, TotalPriceSquares: 225.0, MinPrice: 15.0, MaxPrice: 15.0,
+			TotalDeadlineDays: 2, MinDeadlineDays: 2, MaxDeadlineDays: 2, CheapestCount: 1},
+		{Carrier: "EXPRESSO FR", QuoteCount: 1, OfferCount: 2, TotalPrice: 35.0, TotalPriceSquares: 625.0, MinPrice: 15.0, MaxPrice: 20.0,
+			TotalDeadlineDays: 7, MinDeadlineDays: 3, MaxDeadlineDays: 4, CheapestCount: 1},
+	}, aggregates)
+	assert.Empty(t, domain.QuoteResponse{}.CarrierAggregates())
+}
+
+func TestCarrierAggregate_Metrics(t *testing.T) {
+	aggregate := domain.CarrierAggregate{
+		Carrier: "EXPRESSO FR", QuoteCount: 4, OfferCount: 4, TotalPrice: 80.0, TotalPriceSquares: 1700.0,
+		MinPrice: 15.0, MaxPrice: 25.0, TotalDeadlineDays: 10, MinDeadlineDays: 2, MaxDeadlineDays: 3, CheapestCount: 1,
+	}
+
+	metrics := aggregate.Metrics()
+
+	assert.Equal(t, domain.QuoteMetrics{
+		CarrierName: "EXPRESSO FR", TotalQuotes: 4, TotalShippingPrice: 80.0, AverageShippingPrice: 20.0,
+		MinShippingPrice: 15.0, MaxShippingPrice: 25.0, StddevShippingPrice: 5.0,
+		AverageDeliveryDays: 2.5, MinDeliveryDays: 2, MaxDeliveryDays: 3, CheapestCount: 1, CheapestShare: 0.25,
+	}, metrics)
+	assert.Nil(t, metrics.MedianShippingPrice)
+}
+
+func TestCarrierAggregate_MetricsRoundingNeverNegative(t *testing.T) {
+	aggregate := domain.CarrierAggregate{Carrier: "Correios", QuoteCount: 3, OfferCount: 3, TotalPrice: 0.3, TotalPriceSquares: 0.03}
+
+	stddev := aggregate.Metrics().StddevShippingPrice
+	assert.False(t, math.IsNaN(stddev))
+	assert.InDelta(t, 0.0, stddev, 1e-6)
+	assert.Equal(t, domain.QuoteMetrics{}, domain.CarrierAggregate{}.Metrics())
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "strings"
+
+// ValidCPF verifica o tamanho e os dígitos verificadores de um CPF. Pontuação é ignorada.
+func ValidCPF(cpf string) bool {
+	digits := digitsOnly(cpf)
+	if len(digits) != 11 || repeatedDigits(digits) {
+		return false
+	}
+	return checkDigit(digits[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[9] &&
+		checkDigit(digits[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[10]
+}
+
+// ValidCNPJ verifica o tamanho e os dígitos verificadores de um CNPJ. Pontuação é ignorada.
+func ValidCNPJ(cnpj string) bool {
+	digits := digitsOnly(cnpj)
+	if len(digits) != 14 || repeatedDigits(digits) {
+		return false
+	}
+	return checkDigit(digits[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[12] &&
+		checkDigit(digits[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == digits[13]
+}
+
+// checkDigit calcula o dígito verificador (módulo 11) de um número com os pesos informados
+func checkDigit(digits string, weights []int) byte {
+	sum := 0
+	for i, weight := range weights {
+		sum += int(digits[i]-'0') * weight
+	}
+	remainder := sum % 11
+	if remainder < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - remainder)
+}
+
+// repeatedDigits identifica números como 111.111.111-11, que passam no cálculo mas são inválidos
+func repeatedDigits(digits string) bool {
+	return strings.Count(digits, digits[:1]) == len(digits)
+}
+
+// RecipientDocuments retorna o CPF/CNPJ e a inscrição estadual do destinatário sem pontuação
+func (r QuoteRequest) RecipientDocuments() (registeredNumber, stateInscription string) {
+	registeredNumber = digitsOnly(r.Recipient.RegisteredNumber)
+	if r.Recipient.StateInscription != "" {
+		stateInscription = normalizeStateInscription(r.Recipient.StateInscription)
+	}
+	return registeredNumber, stateInscription
+}
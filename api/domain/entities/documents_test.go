@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestValidCPF(t *testing.T) {
+	assert.True(t, domain.ValidCPF("52998224725"))
+	assert.True(t, domain.ValidCPF("529.982.247-25"))
+
+	assert.False(t, domain.ValidCPF("52998224724"))
+	assert.False(t, domain.ValidCPF("11111111111"))
+	assert.False(t, domain.ValidCPF("5299822472"))
+	assert.False(t, domain.ValidCPF(""))
+}
+
+func TestValidCNPJ(t *testing.T) {
+	assert.True(t, domain.ValidCNPJ("11222333000181"))
+	assert.True(t, domain.ValidCNPJ("11.222.333/0001-81"))
+	assert.True(t, domain.ValidCNPJ("25438296000158"))
+
+	assert.False(t, domain.ValidCNPJ("11222333000182"))
+	assert.False(t, domain.ValidCNPJ("00000000000000"))
+	assert.False(t, domain.ValidCNPJ("1122233300018"))
+	assert.False(t, domain.ValidCNPJ(""))
+}
+
+func TestQuoteRequest_RecipientDocuments(t *testing.T) {
+	request := newValidQuoteRequest()
+	request.Recipient.Type = domain.RecipientTypeCompany
+	request.Recipient.RegisteredNumber = "11.222.333/0001-81"
+	request.Recipient.StateInscription = "isento"
+
+	registeredNumber, stateInscription := request.RecipientDocuments()
+
+	assert.Equal(t, "11222333000181", registeredNumber)
+	assert.Equal(t, "ISENTO", stateInscription)
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorCode é o identificador estável de uma categoria de erro, exposto aos clientes da API
+type ErrorCode string
+
+const (
+	CodeInvalidRequest      ErrorCode = "invalid_request"
+	CodeValidationFailed    ErrorCode = "validation_failed"
+	CodeUpstreamUnavailable ErrorCode = "upstream_unavailable"
+	CodeUpstreamRejected    ErrorCode = "upstream_rejected"
+	CodePersistenceFailure  ErrorCode = "persistence_failure"
+	CodeNotFound            ErrorCode = "not_found"
+	CodeNotAcceptable       ErrorCode = "not_acceptable"
+	CodeTimeout             ErrorCode = "timeout"
+	CodeInternal            ErrorCode = "internal_error"
+)
+
+// Error é um erro de domínio classificado. Message pode ser exibida ao cliente;
+// a causa original (Err) deve ir apenas para os logs.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	// RetryAfter sugere quando tentar novamente (usado quando a transportadora está indisponível)
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is permite comparar com os erros sentinela pelo código, ex.: errors.Is(err, domain.ErrNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Erros sentinela para uso com errors.Is
+var (
+	ErrInvalidRequest      = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+	ErrValidationFailed    = &Error{Code: CodeValidationFailed, Message: "validation failed"}
+	ErrUpstreamUnavailable = &Error{Code: CodeUpstreamUnavailable, Message: "shipping provider unavailable"}
+	ErrUpstreamRejected    = &Error{Code: CodeUpstreamRejected, Message: "shipping provider rejected the request"}
+	ErrPersistenceFailure  = &Error{Code: CodePersistenceFailure, Message: "persistence failure"}
+	ErrNotFound            = &Error{Code: CodeNotFound, Message: "resource not found"}
+	ErrNotAcceptable       = &Error{Code: CodeNotAcceptable, Message: "no acceptable representation"}
+	ErrTimeout             = &Error{Code: CodeTimeout, Message: "request timed out"}
+)
+
+func NewInvalidRequestError(message string, cause error) *Error {
+	return &Error{Code: CodeInvalidRequest, Message: message, Err: cause}
+}
+
+func NewUpstreamUnavailableError(cause error) *Error {
+	return &Error{Code: CodeUpstreamUnavailable, Message: "The shipping provider is temporarily unavailable", Err: cause}
+}
+
+func NewUpstreamRejectedError(cause error) *Error {
+	return &Error{Code: CodeUpstreamRejected, Message: "The shipping provider rejected the quote request", Err: cause}
+}
+
+func NewPersistenceError(cause error) *Error {
+	return &Error{Code: CodePersistenceFailure, Message: "The data could not be read or stored", Err: cause}
+}
+
+func NewNotFoundError(message string) *Error {
+	return &Error{Code: CodeNotFound, Message: message}
+}
+
+func NewNotAcceptableError(message string) *Error {
+	return &Error{Code: CodeNotAcceptable, Message: message}
+}
+
+func NewTimeoutError(cause error) *Error {
+	return &Error{Code: CodeTimeout, Message: "The request timed out before the quote was ready", Err: cause}
+}
+
+// ErrorCodeOf retorna o código do primeiro erro classificado na cadeia, ou CodeInternal
+func ErrorCodeOf(err error) ErrorCode {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return CodeValidationFailed
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+
+	return CodeInternal
+}
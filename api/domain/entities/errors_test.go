@@ -0,0 +1,35 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestError_IsMatchesByCode(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("error calling shipping provider: %w", domain.NewUpstreamUnavailableError(cause))
+
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.NotErrorIs(t, err, domain.ErrUpstreamRejected)
+
+	// The raw cause stays reachable for logging
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+func TestErrorCodeOf(t *testing.T) {
+	request := domain.QuoteRequest{}
+
+	assert.Equal(t, domain.CodeValidationFailed, domain.ErrorCodeOf(request.Validate()))
+	assert.Equal(t, domain.CodeNotFound, domain.ErrorCodeOf(fmt.Errorf("wrapped: %w", domain.NewNotFoundError("quote not found"))))
+	assert.Equal(t, domain.CodePersistenceFailure, domain.ErrorCodeOf(domain.NewPersistenceError(errors.New("db down"))))
+	assert.Equal(t, domain.CodeInternal, domain.ErrorCodeOf(errors.New("unclassified")))
+	assert.Equal(t, domain.CodeTimeout, domain.ErrorCodeOf(domain.NewTimeoutError(context.DeadlineExceeded)))
+
+	assert.ErrorIs(t, request.Validate(), domain.ErrValidationFailed)
+}
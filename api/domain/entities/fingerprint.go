@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Fingerprint retorna um hash canônico da solicitação de cotação.
+// Duas solicitações com o mesmo CEP de destino e os mesmos volumes (em qualquer ordem)
+// produzem o mesmo fingerprint; campos que não afetam o preço, como o SKU, são ignorados.
+// Os centros de distribuição também são comparados independentemente da ordem.
+func (r QuoteRequest) Fingerprint() string {
+	var b strings.Builder
+	b.WriteString("zipcode=")
+	b.WriteString(digitsOnly(r.Recipient.Address.Zipcode))
+	for _, v := range fingerprintVolumes(r.Volumes) {
+		b.WriteString("|volume=")
+		b.WriteString(v)
+	}
+
+	dispatchers := make([]string, 0, len(r.Dispatchers))
+	for _, d := range r.Dispatchers {
+		dispatchers = append(dispatchers, digitsOnly(d.RegisteredNumber)+","+digitsOnly(d.Zipcode)+
+			"["+strings.Join(fingerprintVolumes(d.Volumes), ";")+"]")
+	}
+	sort.Strings(dispatchers)
+	for _, d := range dispatchers {
+		b.WriteString("|dispatcher=")
+		b.WriteString(d)
+	}
+
+	// As opções só entram no fingerprint quando diferem do padrão,
+	// assim solicitações sem elas mantêm o fingerprint anterior
+	simulationTypes := append([]int(nil), r.SimulationTypes()...)
+	sort.Ints(simulationTypes)
+	if len(simulationTypes) != 1 || simulationTypes[0] != SimulationTypeFractional {
+		for _, simulationType := range simulationTypes {
+			b.WriteString("|simulation=")
+			b.WriteString(strconv.Itoa(simulationType))
+		}
+	}
+	registeredNumber, stateInscription := r.RecipientDocuments()
+	if r.Recipient.Type != RecipientTypeIndividual || registeredNumber != "" || stateInscription != "" {
+		b.WriteString("|recipient=")
+		b.WriteString(strconv.Itoa(r.Recipient.Type) + "," + registeredNumber + "," + stateInscription)
+	}
+	if r.Returns != (QuoteReturns{}) {
+		b.WriteString("|returns=")
+		b.WriteString(strconv.FormatBool(r.Returns.Composition) + "," +
+			strconv.FormatBool(r.Returns.Volumes) + "," + strconv.FormatBool(r.Returns.AppliedRules))
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprintVolumes retorna a representação canônica e ordenada dos volumes
+func fingerprintVolumes(volumes []Volume) []string {
+	result := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		result = append(result, strings.Join([]string{
+			strconv.Itoa(v.Category),
+			strconv.Itoa(v.Amount),
+			formatFingerprintFloat(v.UnitaryWeight),
+			formatFingerprintFloat(v.Price),
+			formatFingerprintFloat(v.Height),
+			formatFingerprintFloat(v.Width),
+			formatFingerprintFloat(v.Length),
+		}, ","))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// formatFingerprintFloat normaliza valores decimais para que 0.2 e 0.20000000001 gerem o mesmo texto
+func formatFingerprintFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
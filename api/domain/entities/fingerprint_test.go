@@ -0,0 +1,108 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func newFingerprintRequest(zipcode string, volumes ...domain.Volume) domain.QuoteRequest {
+	request := domain.QuoteRequest{}
+	request.Recipient.Address.Zipcode = zipcode
+	request.Volumes = volumes
+	return request
+}
+
+func TestQuoteRequest_Fingerprint(t *testing.T) {
+	volume1 := domain.Volume{Category: 7, Amount: 1, UnitaryWeight: 5, Price: 349, SKU: "abc-teste-123", Height: 0.2, Width: 0.2, Length: 0.2}
+	volume2 := domain.Volume{Category: 7, Amount: 2, UnitaryWeight: 4, Price: 556, SKU: "abc-teste-527", Height: 0.4, Width: 0.6, Length: 0.15}
+
+	base := newFingerprintRequest("01311000", volume1, volume2).Fingerprint()
+	assert.Len(t, base, 64)
+
+	// Volume order, zipcode formatting, SKU and float noise do not change the fingerprint
+	assert.Equal(t, base, newFingerprintRequest("01311000", volume2, volume1).Fingerprint())
+	assert.Equal(t, base, newFingerprintRequest("01311-000", volume1, volume2).Fingerprint())
+
+	renamed := volume1
+	renamed.SKU = "other-sku"
+	assert.Equal(t, base, newFingerprintRequest("01311000", renamed, volume2).Fingerprint())
+
+	noisy := volume1
+	noisy.Height = 0.2000000001
+	assert.Equal(t, base, newFingerprintRequest("01311000", noisy, volume2).Fingerprint())
+
+	// Anything that affects the price does
+	assert.NotEqual(t, base, newFingerprintRequest("01311001", volume1, volume2).Fingerprint())
+
+	heavier := volume1
+	heavier.UnitaryWeight = 6
+	assert.NotEqual(t, base, newFingerprintRequest("01311000", heavier, volume2).Fingerprint())
+
+	assert.NotEqual(t, base, newFingerprintRequest("01311000", volume1).Fingerprint())
+}
+
+func TestQuoteRequest_FingerprintDispatchers(t *testing.T) {
+	volume1 := domain.Volume{Category: 7, Amount: 1, UnitaryWeight: 5, Price: 349, Height: 0.2, Width: 0.2, Length: 0.2}
+	volume2 := domain.Volume{Category: 7, Amount: 2, UnitaryWeight: 4, Price: 556, Height: 0.4, Width: 0.6, Length: 0.15}
+
+	withDispatchers := func(dispatchers ...domain.Dispatcher) string {
+		request := newFingerprintRequest("01311000")
+		request.Dispatchers = dispatchers
+		return request.Fingerprint()
+	}
+
+	warehouseA := domain.Dispatcher{RegisteredNumber: "25438296000158", Zipcode: "29161376", Volumes: []domain.Volume{volume1}}
+	warehouseB := domain.Dispatcher{RegisteredNumber: "11222333000181", Zipcode: "01311000", Volumes: []domain.Volume{volume2}}
+
+	base := withDispatchers(warehouseA, warehouseB)
+
+	// Dispatcher order does not matter
+	assert.Equal(t, base, withDispatchers(warehouseB, warehouseA))
+
+	// Moving a volume to another warehouse does
+	moved := warehouseB
+	moved.Volumes = []domain.Volume{volume1}
+	assert.NotEqual(t, base, withDispatchers(warehouseA, moved))
+
+	// Dispatcher volumes are not the same as default volumes
+	assert.NotEqual(t, newFingerprintRequest("01311000", volume1).Fingerprint(), withDispatchers(domain.Dispatcher{Volumes: []domain.Volume{volume1}}))
+}
+
+func TestQuoteRequest_FingerprintOptions(t *testing.T) {
+	volume := domain.Volume{Category: 7, Amount: 1, UnitaryWeight: 5, Price: 349, Height: 0.2, Width: 0.2, Length: 0.2}
+	base := newFingerprintRequest("01311000", volume)
+
+	// Explicit defaults keep the fingerprint of a request without options
+	explicit := base
+	explicit.SimulationType = []int{domain.SimulationTypeFractional}
+	assert.Equal(t, base.Fingerprint(), explicit.Fingerprint())
+
+	fullLoad := base
+	fullLoad.SimulationType = []int{domain.SimulationTypeFullLoad, domain.SimulationTypeFractional}
+	assert.NotEqual(t, base.Fingerprint(), fullLoad.Fingerprint())
+
+	reordered := base
+	reordered.SimulationType = []int{domain.SimulationTypeFractional, domain.SimulationTypeFullLoad}
+	assert.Equal(t, fullLoad.Fingerprint(), reordered.Fingerprint())
+
+	withComposition := base
+	withComposition.Returns.Composition = true
+	assert.NotEqual(t, base.Fingerprint(), withComposition.Fingerprint())
+}
+
+func TestQuoteRequest_FingerprintRecipient(t *testing.T) {
+	volume := domain.Volume{Category: 7, Amount: 1, UnitaryWeight: 5, Price: 349, Height: 0.2, Width: 0.2, Length: 0.2}
+	base := newFingerprintRequest("01311000", volume)
+
+	company := base
+	company.Recipient.Type = domain.RecipientTypeCompany
+	company.Recipient.RegisteredNumber = "11222333000181"
+	assert.NotEqual(t, base.Fingerprint(), company.Fingerprint())
+
+	// Document punctuation does not matter
+	formatted := company
+	formatted.Recipient.RegisteredNumber = "11.222.333/0001-81"
+	assert.Equal(t, company.Fingerprint(), formatted.Fingerprint())
+}
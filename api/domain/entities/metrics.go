@@ -2,14 +2,21 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
-// QuoteMetrics representa métricas para uma transportadora específica
-// @Description Métricas de cotações para uma transportadora específica
+// QuoteMetrics representa métricas para uma transportadora, um serviço ou um modal
+// @Description Métricas de cotações para uma transportadora, um serviço ou um modal
 type QuoteMetrics struct {
-	// Nome da transportadora
+	// Nome da transportadora, ausente quando as métricas são agregadas por modal
 	// @example "EXPRESSO FR"
-	CarrierName string `json:"carrier_name"`
+	CarrierName string `json:"carrier_name,omitempty"`
+	// Serviço, presente quando as métricas são agregadas por transportadora e serviço
+	// @example "Rodoviário"
+	Service string `json:"service,omitempty"`
+	// Modal de transporte, presente quando as métricas são agregadas por modal
+	// @example "Rodoviário"
+	Modal string `json:"modal,omitempty"`
 	// Total de cotações realizadas
 	// @example 10
 	TotalQuotes int `json:"total_quotes"`
@@ -19,6 +26,39 @@ type QuoteMetrics struct {
 	// Valor médio dos fretes cotados
 	// @example 15.05
 	AverageShippingPrice float64 `json:"average_shipping_price"`
+	// Menor valor de frete cotado
+	// @example 12.50
+	MinShippingPrice float64 `json:"min_shipping_price"`
+	// Maior valor de frete cotado
+	// @example 27.90
+	MaxShippingPrice float64 `json:"max_shipping_price"`
+	// Mediana dos valores de frete, ausente com percentiles=false
+	// @example 14.80
+	MedianShippingPrice *float64 `json:"median_shipping_price,omitempty"`
+	// Percentil 90 dos valores de frete, ausente com percentiles=false
+	// @example 22.10
+	P90ShippingPrice *float64 `json:"p90_shipping_price,omitempty" gorm:"column:p90_shipping_price"`
+	// Percentil 95 dos valores de frete, ausente com percentiles=false
+	// @example 25.30
+	P95ShippingPrice *float64 `json:"p95_shipping_price,omitempty" gorm:"column:p95_shipping_price"`
+	// Desvio padrão (populacional) dos valores de frete
+	// @example 4.12
+	StddevShippingPrice float64 `json:"stddev_shipping_price"`
+	// Prazo médio de entrega, em dias
+	// @example 3.4
+	AverageDeliveryDays float64 `json:"average_delivery_days"`
+	// Menor prazo de entrega, em dias
+	// @example 1
+	MinDeliveryDays int `json:"min_delivery_days"`
+	// Maior prazo de entrega, em dias
+	// @example 7
+	MaxDeliveryDays int `json:"max_delivery_days"`
+	// Quantidade de cotações em que a transportadora teve a oferta mais barata (empates contam para todas)
+	// @example 4
+	CheapestCount int `json:"cheapest_count"`
+	// Fração, entre 0 e 1, das cotações com oferta da transportadora em que ela foi a mais barata
+	// @example 0.4
+	CheapestShare float64 `json:"cheapest_share"`
 }
 
 // MetricsResponse é a resposta completa de métricas
@@ -30,6 +70,44 @@ type MetricsResponse struct {
 	// Informações sobre cotações mais baratas e mais caras
 	// @Description Detalhes sobre os valores mínimos e máximos de frete
 	CheapestAndMostExpensive CheapestAndMostExpensive `json:"cheapest_and_most_expensive"`
+	// Métricas separadas por região do destinatário, ausentes sem group_by ou sem cotações
+	// @Description Métricas por transportadora em cada região, em ordem alfabética
+	Regions []RegionMetrics `json:"regions,omitempty"`
+	// Janela de cotações considerada no cálculo
+	// @Description Período, fuso horário e limite de cotações aplicados
+	Window MetricsWindow `json:"window"`
+}
+
+// RegionMetrics são as métricas das cotações destinadas a uma região
+// @Description Métricas por transportadora para uma região de destino
+type RegionMetrics struct {
+	// UF ou prefixo de CEP, ou "unknown" quando o CEP não pertence a nenhuma região
+	// @example "BA"
+	Region string `json:"region"`
+	// Métricas por transportadora na região
+	CarrierMetrics []QuoteMetrics `json:"carrier_metrics"`
+	// Fretes mais barato e mais caro na região
+	CheapestAndMostExpensive CheapestAndMostExpensive `json:"cheapest_and_most_expensive"`
+}
+
+// MetricsWindow descreve a janela de cotações usada no cálculo das métricas
+// @Description Janela de cotações considerada nas métricas
+type MetricsWindow struct {
+	// Início do período (inclusivo), ausente quando não há limite inferior
+	// @example "2025-01-01T00:00:00-03:00"
+	From *time.Time `json:"from,omitempty"`
+	// Fim do período (exclusivo), ausente quando não há limite superior
+	// @example "2025-02-01T00:00:00-03:00"
+	To *time.Time `json:"to,omitempty"`
+	// Fuso horário da consulta
+	// @example "America/Sao_Paulo"
+	Timezone string `json:"timezone"`
+	// Período relativo solicitado
+	// @example "this_month"
+	Period MetricsPeriod `json:"period,omitempty"`
+	// Quantidade de cotações mais recentes consideradas, ausente quando todas
+	// @example 10
+	LastQuotes int `json:"last_quotes,omitempty"`
 }
 
 // CheapestAndMostExpensive representa os fretes mais baratos e mais caros
@@ -45,5 +123,28 @@ type CheapestAndMostExpensive struct {
 
 // MetricsRepository define a interface para operações de métricas
 type MetricsRepository interface {
-	GetMetrics(ctx context.Context, lastQuotes int) (*MetricsResponse, error)
+	GetMetrics(ctx context.Context, query MetricsQuery) (*MetricsResponse, error)
+	GetMetricsSeries(ctx context.Context, query MetricsSeriesQuery) (*MetricsSeries, error)
+}
+
+// MetricsRow é uma linha das métricas em formato tabular, usada nas exportações CSV e NDJSON
+type MetricsRow struct {
+	// Região do destinatário; vazia nas métricas de todas as regiões
+	Region string `json:"region,omitempty"`
+	QuoteMetrics
+}
+
+// Rows achata a resposta em linhas: primeiro as métricas de todas as regiões, depois as de
+// cada região, na ordem da resposta
+func (m MetricsResponse) Rows() []MetricsRow {
+	rows := make([]MetricsRow, 0, len(m.CarrierMetrics))
+	for _, metrics := range m.CarrierMetrics {
+		rows = append(rows, MetricsRow{QuoteMetrics: metrics})
+	}
+	for _, region := range m.Regions {
+		for _, metrics := range region.CarrierMetrics {
+			rows = append(rows, MetricsRow{Region: region.Region, QuoteMetrics: metrics})
+		}
+	}
+	return rows
 }
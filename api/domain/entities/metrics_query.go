@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricsPeriod é um período relativo pré-definido para as métricas
+type MetricsPeriod string
+
+const (
+	// PeriodLast24Hours considera as últimas 24 horas
+	PeriodLast24Hours MetricsPeriod = "last_24h"
+	// PeriodLast7Days considera os últimos 7 dias
+	PeriodLast7Days MetricsPeriod = "last_7d"
+	// PeriodLast30Days considera os últimos 30 dias
+	PeriodLast30Days MetricsPeriod = "last_30d"
+	// PeriodToday considera o dia corrente no fuso horário da consulta
+	PeriodToday MetricsPeriod = "today"
+	// PeriodThisMonth considera o mês corrente no fuso horário da consulta
+	PeriodThisMonth MetricsPeriod = "this_month"
+)
+
+// MetricsRegion é a dimensão regional usada para separar as métricas
+type MetricsRegion string
+
+const (
+	// RegionState separa as métricas pela UF do destinatário, derivada do CEP
+	RegionState MetricsRegion = "state"
+	// RegionCepPrefix separa as métricas pelos primeiros dígitos do CEP do destinatário
+	RegionCepPrefix MetricsRegion = "cep_prefix"
+)
+
+// MetricsKey define a chave de agregação de cada linha das métricas
+type MetricsKey string
+
+const (
+	// KeyCarrier agrega por transportadora (padrão)
+	KeyCarrier MetricsKey = "carrier"
+	// KeyCarrierService agrega por transportadora e serviço
+	KeyCarrierService MetricsKey = "carrier_service"
+	// KeyModal agrega por modal de transporte
+	KeyModal MetricsKey = "modal"
+)
+
+const (
+	// DefaultCepPrefixLength é a quantidade de dígitos do prefixo quando não informada
+	DefaultCepPrefixLength = 3
+	// RegionUnknown identifica as cotações cujo CEP não pertence a nenhuma região
+	RegionUnknown = "unknown"
+)
+
+// MetricsQuery define quais cotações entram no cálculo das métricas
+type MetricsQuery struct {
+	// Início do período (inclusivo)
+	From *time.Time
+	// Fim do período (exclusivo)
+	To *time.Time
+	// Período relativo, alternativo a From/To
+	Period MetricsPeriod
+	// Fuso horário usado para os períodos relativos e para exibir a janela; UTC quando nil
+	Location *time.Location
+	// Considera apenas as N cotações mais recentes da janela; 0 considera todas
+	LastQuotes int
+	// Separa as métricas por região do destinatário; vazio não separa
+	Region MetricsRegion
+	// Dígitos do prefixo do CEP quando Region é RegionCepPrefix
+	CepPrefixLength int
+	// Chave de agregação das linhas; vazio agrega por transportadora
+	Key MetricsKey
+	// Dispensa a mediana e os percentis, que exigem ler todas as ofertas
+	SkipPercentiles bool
+}
+
+// Validate verifica a consulta e retorna todas as violações de uma vez, ou nil quando é válida
+func (q MetricsQuery) Validate() error {
+	validationErr := &ValidationError{}
+	q.validate(validationErr)
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+func (q MetricsQuery) validate(validationErr *ValidationError) {
+	if q.LastQuotes < 0 {
+		validationErr.add("last_quotes", "Last quotes must be a positive integer")
+	}
+	if q.Period != "" {
+		switch q.Period {
+		case PeriodLast24Hours, PeriodLast7Days, PeriodLast30Days, PeriodToday, PeriodThisMonth:
+		default:
+			validationErr.add("period", "Period must be one of last_24h, last_7d, last_30d, today, this_month")
+		}
+		if q.From != nil || q.To != nil {
+			validationErr.add("period", "Period cannot be combined with from or to")
+		}
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		validationErr.add("to", "To must be after from")
+	}
+	switch q.Region {
+	case "", RegionState:
+		if q.CepPrefixLength != 0 {
+			validationErr.add("cep_prefix_length", "Cep prefix length requires group_by=cep_prefix")
+		}
+	case RegionCepPrefix:
+		if q.CepPrefixLength < 1 || q.CepPrefixLength > 5 {
+			validationErr.add("cep_prefix_length", "Cep prefix length must be between 1 and 5")
+		}
+	default:
+		validationErr.add("group_by", "Group by must combine at most one of state, cep_prefix with one of carrier, carrier_service, modal")
+	}
+	switch q.Key {
+	case "", KeyCarrier, KeyCarrierService, KeyModal:
+	default:
+		validationErr.add("group_by", "Group by must combine at most one of state, cep_prefix with one of carrier, carrier_service, modal")
+	}
+}
+
+// Unbounded indica se a consulta considera todas as cotações, agregadas por transportadora
+// e sem separação por região
+func (q MetricsQuery) Unbounded() bool {
+	return q.From == nil && q.To == nil && q.Period == "" && q.LastQuotes == 0 &&
+		q.Region == "" && (q.Key == "" || q.Key == KeyCarrier)
+}
+
+// Aggregated indica se a consulta pode ser atendida pelos agregados acumulados: ela precisa
+// considerar todas as cotações e dispensar os percentis, que não podem ser acumulados
+func (q MetricsQuery) Aggregated() bool {
+	return q.SkipPercentiles && q.Unbounded()
+}
+
+// Fingerprint retorna um hash canônico da consulta, antes de Resolve, usado como chave de cache.
+// Consultas equivalentes, com os parâmetros em qualquer ordem, produzem o mesmo fingerprint.
+func (q MetricsQuery) Fingerprint() string {
+	key := q.Key
+	if key == "" {
+		key = KeyCarrier
+	}
+
+	parts := []string{
+		"from=" + formatFingerprintTime(q.From),
+		"to=" + formatFingerprintTime(q.To),
+		"period=" + string(q.Period),
+		"timezone=" + q.location().String(),
+		"last_quotes=" + strconv.Itoa(q.LastQuotes),
+		"region=" + string(q.Region),
+		"cep_prefix_length=" + strconv.Itoa(q.CepPrefixLength),
+		"key=" + string(key),
+		"skip_percentiles=" + strconv.FormatBool(q.SkipPercentiles),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatFingerprintTime normaliza o instante para UTC, para que o fuso de entrada não altere o hash
+func formatFingerprintTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Resolve converte o período relativo em um intervalo absoluto a partir de now
+func (q MetricsQuery) Resolve(now time.Time) MetricsQuery {
+	now = now.In(q.location())
+
+	var from, to time.Time
+	switch q.Period {
+	case PeriodLast24Hours:
+		from, to = now.Add(-24*time.Hour), now
+	case PeriodLast7Days:
+		from, to = now.AddDate(0, 0, -7), now
+	case PeriodLast30Days:
+		from, to = now.AddDate(0, 0, -30), now
+	case PeriodToday:
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to = from.AddDate(0, 0, 1)
+	case PeriodThisMonth:
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		to = from.AddDate(0, 1, 0)
+	default:
+		return q
+	}
+
+	q.From, q.To = &from, &to
+	return q
+}
+
+// Window retorna a janela aplicada, com as datas no fuso horário da consulta
+func (q MetricsQuery) Window() MetricsWindow {
+	loc := q.location()
+	window := MetricsWindow{
+		Timezone:   loc.String(),
+		Period:     q.Period,
+		LastQuotes: q.LastQuotes,
+	}
+	if q.From != nil {
+		from := q.From.In(loc)
+		window.From = &from
+	}
+	if q.To != nil {
+		to := q.To.In(loc)
+		window.To = &to
+	}
+	return window
+}
+
+func (q MetricsQuery) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+	return q.Location
+}
@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestMetricsQuery_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	assert.NoError(t, domain.MetricsQuery{}.Validate())
+	assert.NoError(t, domain.MetricsQuery{Period: domain.PeriodThisMonth, LastQuotes: 10}.Validate())
+
+	err := domain.MetricsQuery{From: &from, To: &to, LastQuotes: -1}.Validate()
+	assert.Equal(t, []string{"last_quotes", "to"}, validationFields(t, err))
+
+	err = domain.MetricsQuery{Period: "yesterday"}.Validate()
+	assert.Equal(t, []string{"period"}, validationFields(t, err))
+
+	err = domain.MetricsQuery{Period: domain.PeriodToday, From: &from}.Validate()
+	assert.Equal(t, []string{"period"}, validationFields(t, err))
+
+	assert.NoError(t, domain.MetricsQuery{Region: domain.RegionState}.Validate())
+	assert.NoError(t, domain.MetricsQuery{Region: domain.RegionCepPrefix, CepPrefixLength: 5}.Validate())
+
+	err = domain.MetricsQuery{Region: "carrier"}.Validate()
+	assert.Equal(t, []string{"group_by"}, validationFields(t, err))
+
+	err = domain.MetricsQuery{Region: domain.RegionCepPrefix, CepPrefixLength: 6}.Validate()
+	assert.Equal(t, []string{"cep_prefix_length"}, validationFields(t, err))
+
+	err = domain.MetricsQuery{Region: domain.RegionState, CepPrefixLength: 2}.Validate()
+	assert.Equal(t, []string{"cep_prefix_length"}, validationFields(t, err))
+
+	assert.NoError(t, domain.MetricsQuery{Region: domain.RegionState, Key: domain.KeyModal}.Validate())
+
+	err = domain.MetricsQuery{Key: "service"}.Validate()
+	assert.Equal(t, []string{"group_by"}, validationFields(t, err))
+}
+
+func TestMetricsQuery_Resolve(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+
+	// 01:30 UTC on Feb 1st is still January 31st in São Paulo
+	now := time.Date(2025, 2, 1, 1, 30, 0, 0, time.UTC)
+
+	query := domain.MetricsQuery{Period: domain.PeriodThisMonth, Location: saoPaulo}.Resolve(now)
+	assert.True(t, time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC).Equal(*query.From))
+	assert.True(t, time.Date(2025, 2, 1, 3, 0, 0, 0, time.UTC).Equal(*query.To))
+
+	query = domain.MetricsQuery{Period: domain.PeriodToday, Location: saoPaulo}.Resolve(now)
+	assert.True(t, time.Date(2025, 1, 31, 3, 0, 0, 0, time.UTC).Equal(*query.From))
+	assert.True(t, time.Date(2025, 2, 1, 3, 0, 0, 0, time.UTC).Equal(*query.To))
+
+	query = domain.MetricsQuery{Period: domain.PeriodLast24Hours}.Resolve(now)
+	assert.True(t, now.Add(-24*time.Hour).Equal(*query.From))
+	assert.True(t, now.Equal(*query.To))
+
+	// Without a period the query is left untouched
+	assert.Equal(t, domain.MetricsQuery{LastQuotes: 5}, domain.MetricsQuery{LastQuotes: 5}.Resolve(now))
+}
+
+func TestMetricsQuery_Window(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+	from := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	window := domain.MetricsQuery{From: &from, Location: saoPaulo, LastQuotes: 10}.Window()
+
+	assert.Equal(t, "America/Sao_Paulo", window.Timezone)
+	assert.Equal(t, "2025-01-01T00:00:00-03:00", window.From.Format(time.RFC3339))
+	assert.Nil(t, window.To)
+	assert.Equal(t, 10, window.LastQuotes)
+	assert.Equal(t, "UTC", domain.MetricsQuery{}.Window().Timezone)
+}
+
+func TestMetricsQuery_Unbounded(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, domain.MetricsQuery{}.Unbounded())
+	assert.True(t, domain.MetricsQuery{Key: domain.KeyCarrier, Location: time.UTC}.Unbounded())
+	assert.False(t, domain.MetricsQuery{From: &from}.Unbounded())
+	assert.False(t, domain.MetricsQuery{Period: domain.PeriodToday}.Unbounded())
+	assert.False(t, domain.MetricsQuery{LastQuotes: 10}.Unbounded())
+	assert.False(t, domain.MetricsQuery{Region: domain.RegionState}.Unbounded())
+	assert.False(t, domain.MetricsQuery{Key: domain.KeyModal}.Unbounded())
+}
+
+func TestMetricsQuery_Aggregated(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The aggregates are opt-in: by default every query reads the offers to compute percentiles
+	assert.False(t, domain.MetricsQuery{}.Aggregated())
+	assert.True(t, domain.MetricsQuery{SkipPercentiles: true}.Aggregated())
+	assert.False(t, domain.MetricsQuery{From: &from, SkipPercentiles: true}.Aggregated())
+	assert.False(t, domain.MetricsQuery{Key: domain.KeyModal, SkipPercentiles: true}.Aggregated())
+}
+
+func TestMetricsQuery_Fingerprint(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+	from := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	sameInstant := from.In(saoPaulo)
+
+	base := domain.MetricsQuery{From: &from, Location: saoPaulo}
+
+	// The same instant written in another time zone and the default key are equivalent
+	assert.Equal(t, base.Fingerprint(), domain.MetricsQuery{From: &sameInstant, Location: saoPaulo}.Fingerprint())
+	assert.Equal(t, domain.MetricsQuery{}.Fingerprint(), domain.MetricsQuery{Key: domain.KeyCarrier, Location: time.UTC}.Fingerprint())
+
+	assert.NotEqual(t, base.Fingerprint(), domain.MetricsQuery{From: &from, Location: time.UTC}.Fingerprint())
+	assert.NotEqual(t, base.Fingerprint(), domain.MetricsQuery{To: &from, Location: saoPaulo}.Fingerprint())
+	assert.NotEqual(t, domain.MetricsQuery{}.Fingerprint(), domain.MetricsQuery{Period: domain.PeriodToday}.Fingerprint())
+	assert.NotEqual(t, domain.MetricsQuery{}.Fingerprint(), domain.MetricsQuery{LastQuotes: 10}.Fingerprint())
+	assert.NotEqual(t,
+		domain.MetricsQuery{Region: domain.RegionCepPrefix, CepPrefixLength: 2}.Fingerprint(),
+		domain.MetricsQuery{Region: domain.RegionCepPrefix, CepPrefixLength: 3}.Fingerprint())
+	assert.NotEqual(t, domain.MetricsQuery{}.Fingerprint(), domain.MetricsQuery{Key: domain.KeyModal}.Fingerprint())
+	assert.NotEqual(t, domain.MetricsQuery{}.Fingerprint(), domain.MetricsQuery{SkipPercentiles: true}.Fingerprint())
+}
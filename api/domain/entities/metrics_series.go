@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetricsBucket é a granularidade dos intervalos de uma série de métricas
+type MetricsBucket string
+
+const (
+	BucketHour MetricsBucket = "hour"
+	BucketDay  MetricsBucket = "day"
+	// BucketWeek agrupa por semana ISO, iniciando na segunda-feira
+	BucketWeek MetricsBucket = "week"
+)
+
+// MaxMetricsSeriesBuckets limita a quantidade de intervalos de uma série
+const MaxMetricsSeriesBuckets = 1000
+
+// duration retorna a duração aproximada do intervalo, usada para limitar a série
+func (b MetricsBucket) duration() time.Duration {
+	switch b {
+	case BucketHour:
+		return time.Hour
+	case BucketDay:
+		return 24 * time.Hour
+	case BucketWeek:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// MetricsSeriesQuery define o período e a granularidade de uma série de métricas
+type MetricsSeriesQuery struct {
+	MetricsQuery
+	// Granularidade dos intervalos
+	Bucket MetricsBucket
+}
+
+// Validate verifica a consulta e retorna todas as violações de uma vez, ou nil quando é válida
+func (q MetricsSeriesQuery) Validate() error {
+	validationErr := &ValidationError{}
+	q.MetricsQuery.validate(validationErr)
+
+	if q.LastQuotes != 0 {
+		validationErr.add("last_quotes", "Last quotes is not supported for time series")
+	}
+	if q.Region != "" || q.Key != "" {
+		validationErr.add("group_by", "Group by is not supported for time series")
+	}
+	if q.Bucket.duration() == 0 {
+		validationErr.add("bucket", "Bucket must be one of hour, day, week")
+	}
+	if q.Period == "" && (q.From == nil || q.To == nil) {
+		validationErr.add("from", "A period or both from and to are required")
+	}
+	if q.From != nil && q.To != nil && q.Bucket.duration() > 0 &&
+		q.To.Sub(*q.From) > MaxMetricsSeriesBuckets*q.Bucket.duration() {
+		validationErr.add("to", fmt.Sprintf("Range must span at most %d buckets", MaxMetricsSeriesBuckets))
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// Resolve converte o período relativo em um intervalo absoluto a partir de now
+func (q MetricsSeriesQuery) Resolve(now time.Time) MetricsSeriesQuery {
+	q.MetricsQuery = q.MetricsQuery.Resolve(now)
+	return q
+}
+
+// MetricsPoint são as métricas de uma transportadora em um intervalo
+// @Description Métricas de uma transportadora em um intervalo da série
+type MetricsPoint struct {
+	// Início do intervalo, no fuso horário da consulta
+	// @example "2025-01-13T00:00:00-03:00"
+	BucketStart time.Time `json:"bucket_start"`
+	// Total de cotações no intervalo
+	// @example 12
+	TotalQuotes int `json:"total_quotes"`
+	// Valor médio dos fretes no intervalo
+	// @example 18.40
+	AverageShippingPrice float64 `json:"average_shipping_price"`
+	// Menor valor de frete no intervalo
+	// @example 12.50
+	MinShippingPrice float64 `json:"min_shipping_price"`
+	// Maior valor de frete no intervalo
+	// @example 27.90
+	MaxShippingPrice float64 `json:"max_shipping_price"`
+}
+
+// CarrierSeries é a série de métricas de uma transportadora
+// @Description Série temporal de métricas de uma transportadora
+type CarrierSeries struct {
+	// Nome da transportadora
+	// @example "EXPRESSO FR"
+	CarrierName string `json:"carrier_name"`
+	// Intervalos com cotações, em ordem cronológica; intervalos sem cotações são omitidos
+	Points []MetricsPoint `json:"points"`
+}
+
+// MetricsSeries é a resposta da série temporal de métricas
+// @Description Métricas por transportadora agrupadas em intervalos de tempo
+type MetricsSeries struct {
+	// Granularidade dos intervalos
+	// @example "day"
+	Bucket MetricsBucket `json:"bucket"`
+	// Janela de cotações considerada
+	Window MetricsWindow `json:"window"`
+	// Séries por transportadora, em ordem alfabética
+	Carriers []CarrierSeries `json:"carriers"`
+}
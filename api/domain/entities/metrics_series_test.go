@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestMetricsSeriesQuery_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	assert.NoError(t, domain.MetricsSeriesQuery{
+		MetricsQuery: domain.MetricsQuery{From: &from, To: &to},
+		Bucket:       domain.BucketHour,
+	}.Validate())
+	assert.NoError(t, domain.MetricsSeriesQuery{
+		MetricsQuery: domain.MetricsQuery{Period: domain.PeriodLast7Days},
+		Bucket:       domain.BucketDay,
+	}.Validate())
+
+	// A range is required and the bucket must be known
+	err := domain.MetricsSeriesQuery{MetricsQuery: domain.MetricsQuery{From: &from}, Bucket: "minute"}.Validate()
+	assert.Equal(t, []string{"bucket", "from"}, validationFields(t, err))
+
+	// Errors of the embedded query are reported too
+	err = domain.MetricsSeriesQuery{MetricsQuery: domain.MetricsQuery{Period: "yesterday"}, Bucket: domain.BucketWeek}.Validate()
+	assert.Equal(t, []string{"period"}, validationFields(t, err))
+
+	// Regional breakdowns are only available on the snapshot
+	err = domain.MetricsSeriesQuery{MetricsQuery: domain.MetricsQuery{Period: domain.PeriodToday, Region: domain.RegionState}, Bucket: domain.BucketHour}.Validate()
+	assert.Equal(t, []string{"group_by"}, validationFields(t, err))
+	err = domain.MetricsSeriesQuery{MetricsQuery: domain.MetricsQuery{Period: domain.PeriodToday, Key: domain.KeyModal}, Bucket: domain.BucketHour}.Validate()
+	assert.Equal(t, []string{"group_by"}, validationFields(t, err))
+
+	// Too many buckets
+	to = from.AddDate(0, 3, 0)
+	err = domain.MetricsSeriesQuery{MetricsQuery: domain.MetricsQuery{From: &from, To: &to}, Bucket: domain.BucketHour}.Validate()
+	assert.Equal(t, []string{"to"}, validationFields(t, err))
+}
+
+func TestMetricsSeriesQuery_Resolve(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	query := domain.MetricsSeriesQuery{
+		MetricsQuery: domain.MetricsQuery{Period: domain.PeriodLast7Days},
+		Bucket:       domain.BucketDay,
+	}.Resolve(now)
+
+	assert.True(t, now.AddDate(0, 0, -7).Equal(*query.From))
+	assert.True(t, now.Equal(*query.To))
+	assert.Equal(t, domain.BucketDay, query.Bucket)
+}
@@ -60,3 +60,22 @@ func TestMetricsResponse(t *testing.T) {
 	assert.Equal(t, 12.50, response.CheapestAndMostExpensive.CheapestShipping)
 	assert.Equal(t, 30.75, response.CheapestAndMostExpensive.MostExpensiveShipping)
 }
+
+func TestMetricsResponse_Rows(t *testing.T) {
+	response := domain.MetricsResponse{
+		CarrierMetrics: []domain.QuoteMetrics{{CarrierName: "Correios", TotalQuotes: 3}, {CarrierName: "EXPRESSO FR", TotalQuotes: 2}},
+		Regions: []domain.RegionMetrics{
+			{Region: "RJ", CarrierMetrics: []domain.QuoteMetrics{{CarrierName: "Correios", TotalQuotes: 1}}},
+			{Region: "SP", CarrierMetrics: []domain.QuoteMetrics{{CarrierName: "Correios", TotalQuotes: 2}, {CarrierName: "EXPRESSO FR", TotalQuotes: 2}}},
+		},
+	}
+
+	assert.Equal(t, []domain.MetricsRow{
+		{QuoteMetrics: domain.QuoteMetrics{CarrierName: "Correios", TotalQuotes: 3}},
+		{QuoteMetrics: domain.QuoteMetrics{CarrierName: "EXPRESSO FR", TotalQuotes: 2}},
+		{Region: "RJ", QuoteMetrics: domain.QuoteMetrics{CarrierName: "Correios", TotalQuotes: 1}},
+		{Region: "SP", QuoteMetrics: domain.QuoteMetrics{CarrierName: "Correios", TotalQuotes: 2}},
+		{Region: "SP", QuoteMetrics: domain.QuoteMetrics{CarrierName: "EXPRESSO FR", TotalQuotes: 2}},
+	}, response.Rows())
+	assert.Empty(t, domain.MetricsResponse{}.Rows())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,6 +17,15 @@ import (
 type QuoteRequest struct {
 	// Informações do destinatário
 	Recipient struct {
+		// Tipo do destinatário: 0 = pessoa física, 1 = pessoa jurídica
+		// @example 0
+		Type int `json:"type"`
+		// CPF ou CNPJ do destinatário (obrigatório para pessoa jurídica)
+		// @example "11222333000181"
+		RegisteredNumber string `json:"registered_number,omitempty"`
+		// Inscrição estadual do destinatário (pessoa jurídica)
+		// @example "123456789"
+		StateInscription string `json:"state_inscription,omitempty"`
 		// Endereço do destinatário
 		Address struct {
 			// CEP do destinatário (obrigatório)
@@ -23,9 +33,62 @@ type QuoteRequest struct {
 			Zipcode string `json:"zipcode"`
 		} `json:"address"`
 	} `json:"recipient"`
-	// Lista de volumes para transporte
+	// Lista de volumes para transporte, expedidos pelo centro de distribuição padrão
 	// @Description Lista de volumes para cálculo de frete
-	// @Required
+	Volumes []Volume `json:"volumes"`
+	// Centros de distribuição de origem, cada um com os seus volumes (opcional)
+	Dispatchers []Dispatcher `json:"dispatchers,omitempty"`
+	// Tipos de simulação: 0 = fracionada, 1 = lotação (padrão: [0])
+	// @example [0]
+	SimulationType []int `json:"simulation_type,omitempty"`
+	// Informações adicionais a retornar em cada oferta (opcional)
+	Returns QuoteReturns `json:"returns"`
+}
+
+// Tipos de destinatário aceitos pelo Frete Rápido
+const (
+	RecipientTypeIndividual = 0
+	RecipientTypeCompany    = 1
+)
+
+// Tipos de simulação aceitos pelo Frete Rápido
+const (
+	SimulationTypeFractional = 0
+	SimulationTypeFullLoad   = 1
+)
+
+// SimulationTypes retorna os tipos de simulação solicitados, ou a simulação fracionada por padrão
+func (r QuoteRequest) SimulationTypes() []int {
+	if len(r.SimulationType) == 0 {
+		return []int{SimulationTypeFractional}
+	}
+	return r.SimulationType
+}
+
+// QuoteReturns seleciona as informações adicionais retornadas em cada oferta
+// @Description Informações adicionais a retornar em cada oferta
+type QuoteReturns struct {
+	// Composição do valor do frete
+	// @example false
+	Composition bool `json:"composition"`
+	// Volumes considerados no cálculo
+	// @example false
+	Volumes bool `json:"volumes"`
+	// Regras aplicadas ao frete
+	// @example false
+	AppliedRules bool `json:"applied_rules"`
+}
+
+// Dispatcher representa um centro de distribuição de origem e os volumes expedidos por ele
+// @Description Centro de distribuição de origem de uma cotação
+type Dispatcher struct {
+	// CNPJ do expedidor (padrão: CNPJ configurado)
+	// @example "25438296000158"
+	RegisteredNumber string `json:"registered_number,omitempty"`
+	// CEP de origem (padrão: CEP configurado)
+	// @example "29161376"
+	Zipcode string `json:"zipcode,omitempty"`
+	// Volumes expedidos por este centro de distribuição
 	Volumes []Volume `json:"volumes"`
 }
 
@@ -66,6 +129,50 @@ type QuoteResponse struct {
 	// Lista de transportadoras com suas cotações
 	// @Description Lista de transportadoras e seus valores
 	Carriers CarriersJSON `json:"carrier" gorm:"column:carrier;type:jsonb"`
+	// CEP do destinatário da cotação
+	// @example "01311000"
+	RecipientZipcode string `json:"recipient_zipcode,omitempty" gorm:"column:recipient_zipcode;type:varchar(8);index"`
+	// Validade da cotação (menor expiração entre as ofertas), usada pelo cache
+	ExpiresAt time.Time `json:"-" gorm:"-"`
+	// Indica se a resposta foi servida a partir do cache
+	CacheHit bool `json:"-" gorm:"-"`
+	// Ofertas agrupadas por centro de distribuição, presente quando a solicitação informa dispatchers
+	Dispatchers []DispatcherQuote `json:"dispatchers,omitempty" gorm:"-"`
+	// Solicitação que originou a cotação, presente nas consultas ao histórico
+	Request *StoredQuoteRequest `json:"request,omitempty" gorm:"foreignKey:QuoteResponseID;constraint:OnDelete:CASCADE"`
+}
+
+// WithoutDetails retorna uma cópia da resposta sem os detalhes das ofertas,
+// mantendo o formato original da API
+func (q QuoteResponse) WithoutDetails() QuoteResponse {
+	carriers := make(CarriersJSON, len(q.Carriers))
+	for i, carrier := range q.Carriers {
+		carrier.Details = nil
+		carriers[i] = carrier
+	}
+	q.Carriers = carriers
+	return q
+}
+
+// GroupByDispatcher retorna uma cópia da resposta com as ofertas também agrupadas por
+// centro de distribuição, na ordem em que aparecem. Ofertas sem origem conhecida são ignoradas.
+func (q QuoteResponse) GroupByDispatcher() QuoteResponse {
+	groups := []DispatcherQuote{}
+	index := map[CarrierDispatcher]int{}
+	for _, carrier := range q.Carriers {
+		if carrier.Dispatcher == nil {
+			continue
+		}
+		i, ok := index[*carrier.Dispatcher]
+		if !ok {
+			i = len(groups)
+			index[*carrier.Dispatcher] = i
+			groups = append(groups, DispatcherQuote{CarrierDispatcher: *carrier.Dispatcher, Carriers: []Carrier{}})
+		}
+		groups[i].Carriers = append(groups[i].Carriers, carrier)
+	}
+	q.Dispatchers = groups
+	return q
 }
 
 // CarriersJSON é um tipo personalizado para serializar como JSONB no PostgreSQL
@@ -111,6 +218,150 @@ type Carrier struct {
 	// Valor do frete
 	// @example 17.00
 	Price float64 `json:"price"`
+	// Prazo de entrega em dias (numérico)
+	// @example 3
+	DeadlineDays int `json:"deadline_days"`
+	// Data estimada de entrega
+	// @example "2025-01-13T00:00:00Z"
+	EstimatedDeliveryDate *time.Time `json:"estimated_delivery_date,omitempty"`
+	// Indica a oferta mais barata da resposta
+	// @example true
+	Cheapest bool `json:"cheapest,omitempty"`
+	// Indica a oferta mais rápida da resposta
+	// @example false
+	Fastest bool `json:"fastest,omitempty"`
+	// Centro de distribuição de origem da oferta
+	Dispatcher *CarrierDispatcher `json:"dispatcher,omitempty"`
+	// Tipo de simulação da oferta: 0 = fracionada (omitido), 1 = lotação
+	// @example 1
+	SimulationType int `json:"simulation_type,omitempty"`
+	// Composição do valor do frete, presente quando solicitada em returns.composition
+	Composition *OfferComposition `json:"composition,omitempty"`
+	// Volumes considerados no cálculo, presentes quando solicitados em returns.volumes
+	Volumes []OfferVolume `json:"volumes,omitempty"`
+	// Regras aplicadas ao frete, presentes quando solicitadas em returns.applied_rules.
+	// O formato varia conforme o tipo de regra e é repassado como recebido da transportadora.
+	AppliedRules json.RawMessage `json:"applied_rules,omitempty" swaggertype:"array,object"`
+	// Detalhes completos da oferta, retornados apenas com ?detail=full
+	Details *CarrierDetails `json:"details,omitempty"`
+}
+
+// OfferComposition detalha como o valor do frete foi composto
+// @Description Composição do valor do frete
+type OfferComposition struct {
+	// Frete peso
+	// @example 61.83
+	FreightWeight float64 `json:"freight_weight"`
+	// Frete peso excedente
+	FreightWeightExcess float64 `json:"freight_weight_excess"`
+	// Frete peso por volume
+	FreightWeightVolume float64 `json:"freight_weight_volume"`
+	// Frete por volume
+	FreightVolume float64 `json:"freight_volume"`
+	// Frete mínimo
+	FreightMinimum float64 `json:"freight_minimum"`
+	// Frete valor (ad valorem)
+	FreightInvoice float64 `json:"freight_invoice"`
+	// Taxas do primeiro subtotal (coleta, entrega, pedágio...), por nome
+	SubTotal1 map[string]float64 `json:"sub_total1,omitempty"`
+	// Taxas do segundo subtotal (TRT, TDA, TDE...), por nome
+	SubTotal2 map[string]float64 `json:"sub_total2,omitempty"`
+	// Taxas do terceiro subtotal (seguro, GRIS...), por nome
+	SubTotal3 map[string]float64 `json:"sub_total3,omitempty"`
+}
+
+// OfferVolume é um volume como considerado pela transportadora no cálculo do frete
+// @Description Volume considerado no cálculo do frete
+type OfferVolume struct {
+	// Categoria do produto
+	// @example "7"
+	Category string `json:"category"`
+	// Quantidade de itens
+	// @example 1
+	Amount int `json:"amount"`
+	// Código SKU do produto
+	// @example "abc-teste-123"
+	SKU string `json:"sku"`
+	// Altura em metros
+	Height float64 `json:"height"`
+	// Largura em metros
+	Width float64 `json:"width"`
+	// Comprimento em metros
+	Length float64 `json:"length"`
+	// Preço unitário
+	UnitaryPrice float64 `json:"unitary_price"`
+	// Peso unitário em kg
+	UnitaryWeight float64 `json:"unitary_weight"`
+}
+
+// CarrierDispatcher identifica o centro de distribuição de origem de uma oferta
+// @Description Centro de distribuição de origem de uma oferta
+type CarrierDispatcher struct {
+	// Identificador do expedidor na simulação do Frete Rápido
+	// @example "67a0f2c8e4b0a1b2c3d4e5f6"
+	ID string `json:"id"`
+	// CNPJ do expedidor
+	// @example "25438296000158"
+	RegisteredNumber string `json:"registered_number"`
+	// CEP de origem
+	// @example "29161376"
+	Zipcode string `json:"zipcode"`
+}
+
+// DispatcherQuote agrupa as ofertas de um mesmo centro de distribuição
+// @Description Ofertas de frete de um centro de distribuição
+type DispatcherQuote struct {
+	CarrierDispatcher
+	// Ofertas deste centro de distribuição
+	Carriers []Carrier `json:"carrier"`
+}
+
+// CarrierDetails preserva os dados da oferta retornados pela API do Frete Rápido
+// @Description Detalhes completos de uma oferta de frete
+type CarrierDetails struct {
+	// CNPJ da transportadora
+	// @example "25438296000158"
+	RegisteredNumber string `json:"registered_number"`
+	// Razão social da transportadora
+	// @example "EXPRESSO FR LTDA"
+	CompanyName string `json:"company_name"`
+	// URL do logotipo da transportadora
+	// @example "https://s3.amazonaws.com/public.prod.freterapido.uploads/transportadora/foto-perfil/25438296000158.png"
+	Logo string `json:"logo"`
+	// Valor de custo do frete
+	// @example 15.50
+	CostPrice float64 `json:"cost_price"`
+	// Valor final do frete
+	// @example 17.00
+	FinalPrice float64 `json:"final_price"`
+	// Pesos considerados no cálculo
+	Weights CarrierWeights `json:"weights"`
+	// Data estimada de entrega informada pela transportadora
+	// @example "2025-01-13"
+	EstimatedDate string `json:"estimated_date"`
+	// Modal de transporte
+	// @example "Rodoviário"
+	Modal string `json:"modal"`
+	// Indica se a entrega é feita em domicílio
+	// @example true
+	HomeDelivery bool `json:"home_delivery"`
+	// Validade da oferta
+	// @example "2025-01-10T12:00:00Z"
+	Expiration time.Time `json:"expiration"`
+}
+
+// CarrierWeights representa os pesos usados no cálculo do frete
+// @Description Pesos real, cubado e utilizado no cálculo (kg)
+type CarrierWeights struct {
+	// Peso real
+	// @example 5.0
+	Real float64 `json:"real"`
+	// Peso cubado
+	// @example 2.4
+	Cubed float64 `json:"cubed"`
+	// Peso utilizado no cálculo
+	// @example 5.0
+	Used float64 `json:"used"`
 }
 
 // Frete Rápido API structure
@@ -121,16 +372,14 @@ type FreteRapidoRequest struct {
 		PlatformCode     string `json:"platform_code"`
 	} `json:"shipper"`
 	Recipient struct {
-		Type    int    `json:"type"`
-		Country string `json:"country"`
-		Zipcode int    `json:"zipcode"`
+		Type             int    `json:"type"`
+		RegisteredNumber string `json:"registered_number,omitempty"`
+		StateInscription string `json:"state_inscription,omitempty"`
+		Country          string `json:"country"`
+		Zipcode          int    `json:"zipcode"`
 	} `json:"recipient"`
-	Dispatchers []struct {
-		RegisteredNumber string              `json:"registered_number"`
-		Zipcode          int                 `json:"zipcode"`
-		Volumes          []FreteRapidoVolume `json:"volumes"`
-	} `json:"dispatchers"`
-	SimulationType []int `json:"simulation_type"`
+	Dispatchers    []FreteRapidoDispatcher `json:"dispatchers"`
+	SimulationType []int                   `json:"simulation_type"`
 	Returns        struct {
 		Composition  bool `json:"composition"`
 		Volumes      bool `json:"volumes"`
@@ -138,6 +387,12 @@ type FreteRapidoRequest struct {
 	} `json:"returns"`
 }
 
+type FreteRapidoDispatcher struct {
+	RegisteredNumber string              `json:"registered_number"`
+	Zipcode          int                 `json:"zipcode"`
+	Volumes          []FreteRapidoVolume `json:"volumes"`
+}
+
 type FreteRapidoVolume struct {
 	Amount        int     `json:"amount"`
 	Category      string  `json:"category"`
@@ -156,7 +411,9 @@ type FreteRapidoResponse struct {
 		RegisteredNumberShipper    string `json:"registered_number_shipper"`
 		RegisteredNumberDispatcher string `json:"registered_number_dispatcher"`
 		ZipcodeOrigin              int    `json:"zipcode_origin"`
-		Offers                     []struct {
+		// Volumes só é preenchido quando returns.volumes é solicitado
+		Volumes []FreteRapidoVolume `json:"volumes"`
+		Offers  []struct {
 			Offer          int    `json:"offer"`
 			TableReference string `json:"table_reference"`
 			SimulationType int    `json:"simulation_type"`
@@ -191,12 +448,53 @@ type FreteRapidoResponse struct {
 				EstimatedDate string `json:"estimated_date"`
 			} `json:"carrier_original_delivery_time"`
 			Modal string `json:"modal"`
+			// Composition e AppliedRules só são preenchidos quando solicitados em returns
+			Composition  *FreteRapidoComposition `json:"composition"`
+			AppliedRules json.RawMessage         `json:"applied_rules"`
 		} `json:"offers"`
 	} `json:"dispatchers"`
 }
 
+type FreteRapidoComposition struct {
+	FreightWeight       float64            `json:"freight_weight"`
+	FreightWeightExcess float64            `json:"freight_weight_excess"`
+	FreightWeightVolume float64            `json:"freight_weight_volume"`
+	FreightVolume       float64            `json:"freight_volume"`
+	FreightMinimum      float64            `json:"freight_minimum"`
+	FreightInvoice      float64            `json:"freight_invoice"`
+	SubTotal1           map[string]float64 `json:"sub_total1"`
+	SubTotal2           map[string]float64 `json:"sub_total2"`
+	SubTotal3           map[string]float64 `json:"sub_total3"`
+}
+
 // Repository interface
 type QuoteRepository interface {
-	SaveQuote(ctx context.Context, quote *QuoteResponse) error
+	// SaveQuote persiste a resposta e a solicitação que a originou em uma única transação
+	SaveQuote(ctx context.Context, quote *QuoteResponse, request QuoteRequest) error
 	GetLastQuotes(ctx context.Context, limit int) ([]QuoteResponse, error)
+	// ListQuotes retorna até filter.Limit cotações, da mais recente para a mais antiga
+	ListQuotes(ctx context.Context, filter QuoteFilter) ([]QuoteResponse, error)
+	// GetQuote retorna uma cotação pelo ID, com a solicitação que a originou, ou ErrNotFound
+	GetQuote(ctx context.Context, id uint) (*QuoteResponse, error)
+	// GetQuoteRequest retorna a solicitação que originou uma cotação, ou ErrNotFound
+	GetQuoteRequest(ctx context.Context, quoteID uint) (*StoredQuoteRequest, error)
+	// ExportQuotes percorre as cotações do filtro, da mais antiga para a mais recente, chamando fn
+	// para cada uma sem carregar todas em memória. Um erro de fn interrompe a leitura e é retornado.
+	ExportQuotes(ctx context.Context, filter QuoteExportFilter, fn func(QuoteResponse) error) error
+}
+
+// ErrCacheMiss é retornado quando a chave não existe no cache
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache define a interface de cache usada pelos casos de uso
+type Cache interface {
+	SetCacheJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	GetCacheJSON(ctx context.Context, key string, dest interface{}) error
+	// IncrementCache incrementa atomicamente o contador em key, iniciando em 0, e retorna o novo valor
+	IncrementCache(ctx context.Context, key string) (int64, error)
+}
+
+// ShippingProvider define a porta para integrações com APIs de cotação de frete
+type ShippingProvider interface {
+	Quote(ctx context.Context, request QuoteRequest) (*QuoteResponse, error)
 }
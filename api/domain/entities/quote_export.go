@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// QuoteExportFilter define quais cotações são exportadas: os mesmos filtros do histórico, sem paginação
+type QuoteExportFilter struct {
+	// Início do período (inclusivo)
+	From *time.Time
+	// Fim do período (exclusivo)
+	To *time.Time
+	// Exporta apenas as ofertas desta transportadora (sem diferenciar maiúsculas)
+	CarrierName string
+	// CEP do destinatário
+	RecipientZipcode string
+}
+
+// Validate verifica o filtro e retorna todas as violações de uma vez, ou nil quando é válido
+func (f QuoteExportFilter) Validate() error {
+	validationErr := &ValidationError{}
+
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		validationErr.add("to", "To must be after from")
+	}
+	if f.RecipientZipcode != "" && !zipcodePattern.MatchString(f.RecipientZipcode) {
+		validationErr.add("zipcode", "Zipcode must have exactly 8 digits")
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// QuoteExportRow é uma oferta de transportadora exportada, uma linha por oferta
+type QuoteExportRow struct {
+	// Cotação à qual a oferta pertence
+	QuoteID uint `json:"quote_id"`
+	// Data da cotação
+	CreatedAt time.Time `json:"created_at"`
+	// CEP do destinatário
+	RecipientZipcode string `json:"recipient_zipcode"`
+	// Nome da transportadora
+	Carrier string `json:"carrier"`
+	// Serviço da oferta
+	Service string `json:"service"`
+	// Modal de transporte, vazio quando a transportadora não informa
+	Modal string `json:"modal"`
+	// Valor do frete
+	Price float64 `json:"price"`
+	// Prazo de entrega em dias
+	DeadlineDays int `json:"deadline_days"`
+	// Data estimada de entrega, quando informada
+	EstimatedDeliveryDate *time.Time `json:"estimated_delivery_date,omitempty"`
+	// Indica a oferta mais barata da cotação
+	Cheapest bool `json:"cheapest"`
+	// Indica a oferta mais rápida da cotação
+	Fastest bool `json:"fastest"`
+}
+
+// ExportRows retorna as ofertas da cotação no formato de exportação, na ordem da resposta.
+// As ofertas mais barata e mais rápida são calculadas entre todas as ofertas da cotação, já que
+// essas marcações não são gravadas. Com carrierName, apenas as ofertas dessa transportadora são retornadas.
+func (q QuoteResponse) ExportRows(carrierName string) []QuoteExportRow {
+	carriers := make(CarriersJSON, len(q.Carriers))
+	copy(carriers, q.Carriers)
+	markCheapestAndFastest(carriers)
+
+	rows := make([]QuoteExportRow, 0, len(carriers))
+	for _, carrier := range carriers {
+		if carrierName != "" && !strings.EqualFold(carrier.Name, carrierName) {
+			continue
+		}
+
+		var modal string
+		if carrier.Details != nil {
+			modal = carrier.Details.Modal
+		}
+		rows = append(rows, QuoteExportRow{
+			QuoteID:               q.ID,
+			CreatedAt:             q.CreatedAt,
+			RecipientZipcode:      q.RecipientZipcode,
+			Carrier:               carrier.Name,
+			Service:               carrier.Service,
+			Modal:                 modal,
+			Price:                 carrier.Price,
+			DeadlineDays:          carrier.DeadlineDays,
+			EstimatedDeliveryDate: carrier.EstimatedDeliveryDate,
+			Cheapest:              carrier.Cheapest,
+			Fastest:               carrier.Fastest,
+		})
+	}
+	return rows
+}
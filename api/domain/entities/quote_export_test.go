@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"gorm.io/gorm"
+)
+
+func TestQuoteExportFilter_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	assert.NoError(t, domain.QuoteExportFilter{}.Validate())
+	assert.NoError(t, domain.QuoteExportFilter{From: &to, To: &from, RecipientZipcode: "01311000"}.Validate())
+
+	err := domain.QuoteExportFilter{From: &from, To: &to, RecipientZipcode: "0131"}.Validate()
+	assert.ErrorIs(t, err, domain.ErrValidationFailed)
+	assert.ElementsMatch(t, []string{"to", "zipcode"}, validationFields(t, err))
+}
+
+func TestQuoteResponse_ExportRows(t *testing.T) {
+	createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	delivery := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	quote := domain.QuoteResponse{
+		Model: gorm.Model{ID: 42, CreatedAt: createdAt},
+		Carriers: []domain.Carrier{
+			{Name: "EXPRESSO FR", Service: "Rodoviário", Price: 17.0, DeadlineDays: 3, EstimatedDeliveryDate: &delivery, Details: &domain.CarrierDetails{Modal: "Rodoviário"}},
+			{Name: "Correios", Service: "SEDEX", Price: 25.5, DeadlineDays: 1},
+			// Flags stored with the offer are ignored, they are recomputed for the whole quote
+			{Name: "Correios", Service: "PAC", Price: 19.9, DeadlineDays: 6, Cheapest: true, Fastest: true},
+		},
+		RecipientZipcode: "01311000",
+	}
+
+	assert.Equal(t, []domain.QuoteExportRow{
+		{QuoteID: 42, CreatedAt: createdAt, RecipientZipcode: "01311000", Carrier: "EXPRESSO FR", Service: "Rodoviário", Modal: "Rodoviário",
+			Price: 17.0, DeadlineDays: 3, EstimatedDeliveryDate: &delivery, Cheapest: true},
+		{QuoteID: 42, CreatedAt: createdAt, RecipientZipcode: "01311000", Carrier: "Correios", Service: "SEDEX",
+			Price: 25.5, DeadlineDays: 1, Fastest: true},
+		{QuoteID: 42, CreatedAt: createdAt, RecipientZipcode: "01311000", Carrier: "Correios", Service: "PAC",
+			Price: 19.9, DeadlineDays: 6},
+	}, quote.ExportRows(""))
+
+	// The carrier filter keeps only that carrier's offers, ignoring case, with the flags
+	// still computed across every offer of the quote
+	rows := quote.ExportRows("correios")
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "Correios", rows[0].Carrier)
+	assert.True(t, rows[0].Fastest)
+	assert.False(t, rows[1].Cheapest)
+
+	// The quote itself is not modified
+	assert.False(t, quote.Carriers[0].Cheapest)
+
+	assert.Empty(t, domain.QuoteResponse{}.ExportRows(""))
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limites da paginação do histórico de cotações
+const (
+	DefaultQuotePageSize = 20
+	MaxQuotePageSize     = 100
+)
+
+// QuoteCursor aponta para a última cotação de uma página, na ordem (created_at, id) decrescente
+type QuoteCursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// Encode serializa o cursor em um texto opaco para ser enviado ao cliente
+func (c QuoteCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeQuoteCursor lê um cursor gerado por Encode
+func DecodeQuoteCursor(value string) (*QuoteCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, errors.New("invalid cursor format")
+	}
+
+	cursor := &QuoteCursor{}
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+	parsedID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor id: %w", err)
+	}
+	cursor.ID = uint(parsedID)
+
+	return cursor, nil
+}
+
+// QuoteFilter define os filtros e a página de uma consulta ao histórico de cotações
+type QuoteFilter struct {
+	// Início do período (inclusivo)
+	From *time.Time
+	// Fim do período (exclusivo)
+	To *time.Time
+	// Nome da transportadora presente em alguma das ofertas (sem diferenciar maiúsculas)
+	CarrierName string
+	// CEP do destinatário
+	RecipientZipcode string
+	// Continua a partir da cotação apontada pelo cursor
+	Cursor *QuoteCursor
+	// Quantidade máxima de cotações
+	Limit int
+}
+
+// Validate verifica o filtro e retorna todas as violações de uma vez, ou nil quando é válido
+func (f QuoteFilter) Validate() error {
+	validationErr := &ValidationError{}
+
+	if f.Limit < 1 || f.Limit > MaxQuotePageSize {
+		validationErr.add("limit", fmt.Sprintf("Limit must be between 1 and %d", MaxQuotePageSize))
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		validationErr.add("to", "To must be after from")
+	}
+	if f.RecipientZipcode != "" && !zipcodePattern.MatchString(f.RecipientZipcode) {
+		validationErr.add("zipcode", "Zipcode must have exactly 8 digits")
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// QuotePage é uma página do histórico de cotações
+// @Description Página do histórico de cotações, da mais recente para a mais antiga
+type QuotePage struct {
+	// Cotações da página
+	Quotes []QuoteResponse `json:"quotes"`
+	// Cursor para a próxima página, ausente na última página
+	// @example "MjAyNS0wMS0xMFQxMjowMDowMFp8NDI"
+	NextCursor string `json:"next_cursor,omitempty"`
+}
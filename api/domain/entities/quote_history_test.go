@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestQuoteCursor_RoundTrip(t *testing.T) {
+	cursor := domain.QuoteCursor{CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 123456789, time.UTC), ID: 42}
+
+	decoded, err := domain.DecodeQuoteCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+}
+
+func TestDecodeQuoteCursor_Invalid(t *testing.T) {
+	for _, value := range []string{"!!!", "bm8tc2VwYXJhdG9y", "MjAyNS0wMS0xMHw0Mg", "MjAyNS0wMS0xMFQxMjowMDowMFp8YWJj"} {
+		_, err := domain.DecodeQuoteCursor(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestQuoteFilter_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+
+	assert.NoError(t, domain.QuoteFilter{Limit: domain.DefaultQuotePageSize}.Validate())
+
+	err := domain.QuoteFilter{From: &from, To: &to, RecipientZipcode: "1311", Limit: domain.MaxQuotePageSize + 1}.Validate()
+	assert.Equal(t, []string{"limit", "to", "zipcode"}, validationFields(t, err))
+}
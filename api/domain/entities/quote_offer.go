@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// QuoteOffer é uma oferta de transportadora armazenada de forma normalizada,
+// uma linha por oferta, para que as métricas sejam agregadas no banco
+type QuoteOffer struct {
+	ID uint `gorm:"primaryKey"`
+	// Cotação à qual a oferta pertence
+	QuoteID uint `gorm:"not null;index"`
+	// Nome da transportadora
+	Carrier string `gorm:"not null;index"`
+	// Serviço da oferta
+	Service string
+	// Modal de transporte, vazio quando a transportadora não informa
+	Modal string `gorm:"not null;default:'';index"`
+	// Valor do frete
+	Price float64 `gorm:"not null"`
+	// Prazo de entrega em dias
+	DeadlineDays int `gorm:"not null;default:0"`
+	// CEP do destinatário, replicado da cotação para agrupar por região sem junção
+	RecipientZipcode string `gorm:"type:varchar(8);index"`
+	// Data da cotação, replicada para filtrar sem junção
+	CreatedAt time.Time `gorm:"not null;index"`
+}
+
+func (QuoteOffer) TableName() string {
+	return "quote_offers"
+}
+
+// Offers retorna as ofertas da cotação no formato normalizado
+func (q QuoteResponse) Offers() []QuoteOffer {
+	offers := make([]QuoteOffer, 0, len(q.Carriers))
+	for _, carrier := range q.Carriers {
+		var modal string
+		if carrier.Details != nil {
+			modal = carrier.Details.Modal
+		}
+		offers = append(offers, QuoteOffer{
+			QuoteID:          q.ID,
+			Carrier:          carrier.Name,
+			Service:          carrier.Service,
+			Modal:            modal,
+			Price:            carrier.Price,
+			DeadlineDays:     carrier.DeadlineDays,
+			RecipientZipcode: q.RecipientZipcode,
+			CreatedAt:        q.CreatedAt,
+		})
+	}
+	return offers
+}
@@ -0,0 +1,30 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"gorm.io/gorm"
+)
+
+func TestQuoteResponse_Offers(t *testing.T) {
+	createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	quote := domain.QuoteResponse{
+		Model: gorm.Model{ID: 42, CreatedAt: createdAt},
+		Carriers: []domain.Carrier{
+			{Name: "EXPRESSO FR", Service: "Rodoviário", Price: 17.0, DeadlineDays: 3, Details: &domain.CarrierDetails{Modal: "Rodoviário"}},
+			{Name: "Correios", Service: "SEDEX", Price: 25.5, DeadlineDays: 1},
+		},
+		RecipientZipcode: "01311000",
+	}
+
+	offers := quote.Offers()
+
+	assert.Equal(t, []domain.QuoteOffer{
+		{QuoteID: 42, Carrier: "EXPRESSO FR", Service: "Rodoviário", Modal: "Rodoviário", Price: 17.0, DeadlineDays: 3, RecipientZipcode: "01311000", CreatedAt: createdAt},
+		{QuoteID: 42, Carrier: "Correios", Service: "SEDEX", Price: 25.5, DeadlineDays: 1, RecipientZipcode: "01311000", CreatedAt: createdAt},
+	}, offers)
+	assert.Empty(t, domain.QuoteResponse{}.Offers())
+}
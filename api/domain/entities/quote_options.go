@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// QuoteSort define a ordenação das ofertas de uma cotação
+type QuoteSort string
+
+const (
+	SortByPrice    QuoteSort = "price"
+	SortByDeadline QuoteSort = "deadline"
+)
+
+// QuoteOptions reúne as opções de ordenação e filtragem aplicadas às ofertas de uma cotação.
+// Os filtros com valor zero não são aplicados.
+type QuoteOptions struct {
+	// Ordenação das ofertas: "price" ou "deadline" (vazio mantém a ordem da transportadora)
+	Sort QuoteSort
+	// Preço máximo aceito
+	MaxPrice float64
+	// Prazo máximo aceito, em dias
+	MaxDays int
+	// Transportadoras permitidas (comparação sem diferenciar maiúsculas)
+	Carriers []string
+	// Transportadoras bloqueadas (comparação sem diferenciar maiúsculas)
+	ExcludeCarriers []string
+	// Modal aceito, ex.: "Rodoviário"
+	Modal string
+}
+
+// Validate verifica as opções e retorna todas as violações de uma vez, ou nil quando são válidas
+func (o QuoteOptions) Validate() error {
+	validationErr := &ValidationError{}
+
+	if o.Sort != "" && o.Sort != SortByPrice && o.Sort != SortByDeadline {
+		validationErr.add("sort", "Sort must be 'price' or 'deadline'")
+	}
+	if o.MaxPrice < 0 {
+		validationErr.add("max_price", "Max price cannot be negative")
+	}
+	if o.MaxDays < 0 {
+		validationErr.add("max_days", "Max days cannot be negative")
+	}
+	for _, name := range o.Carriers {
+		if containsFold(o.ExcludeCarriers, name) {
+			validationErr.add("carriers", "Carrier "+name+" cannot be both allowed and excluded")
+		}
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// ApplyOptions retorna uma cópia da resposta com as ofertas filtradas, ordenadas e
+// marcadas como mais barata/mais rápida. A resposta original não é modificada.
+func (q QuoteResponse) ApplyOptions(options QuoteOptions) QuoteResponse {
+	carriers := make(CarriersJSON, 0, len(q.Carriers))
+	for _, carrier := range q.Carriers {
+		if options.accepts(carrier) {
+			carrier.Cheapest = false
+			carrier.Fastest = false
+			carriers = append(carriers, carrier)
+		}
+	}
+
+	markCheapestAndFastest(carriers)
+
+	switch options.Sort {
+	case SortByPrice:
+		sort.SliceStable(carriers, func(i, j int) bool {
+			if carriers[i].Price != carriers[j].Price {
+				return carriers[i].Price < carriers[j].Price
+			}
+			return carriers[i].DeadlineDays < carriers[j].DeadlineDays
+		})
+	case SortByDeadline:
+		sort.SliceStable(carriers, func(i, j int) bool {
+			if carriers[i].DeadlineDays != carriers[j].DeadlineDays {
+				return carriers[i].DeadlineDays < carriers[j].DeadlineDays
+			}
+			return carriers[i].Price < carriers[j].Price
+		})
+	}
+
+	q.Carriers = carriers
+	return q
+}
+
+func (o QuoteOptions) accepts(carrier Carrier) bool {
+	if o.MaxPrice > 0 && carrier.Price > o.MaxPrice {
+		return false
+	}
+	if o.MaxDays > 0 && carrier.DeadlineDays > o.MaxDays {
+		return false
+	}
+	if len(o.Carriers) > 0 && !containsFold(o.Carriers, carrier.Name) {
+		return false
+	}
+	if containsFold(o.ExcludeCarriers, carrier.Name) {
+		return false
+	}
+	if o.Modal != "" && (carrier.Details == nil || !strings.EqualFold(carrier.Details.Modal, o.Modal)) {
+		return false
+	}
+	return true
+}
+
+// markCheapestAndFastest marca todas as ofertas empatadas no menor preço e no menor prazo
+func markCheapestAndFastest(carriers CarriersJSON) {
+	if len(carriers) == 0 {
+		return
+	}
+
+	minPrice, minDays := carriers[0].Price, carriers[0].DeadlineDays
+	for _, carrier := range carriers[1:] {
+		if carrier.Price < minPrice {
+			minPrice = carrier.Price
+		}
+		if carrier.DeadlineDays < minDays {
+			minDays = carrier.DeadlineDays
+		}
+	}
+
+	for i := range carriers {
+		carriers[i].Cheapest = carriers[i].Price == minPrice
+		carriers[i].Fastest = carriers[i].DeadlineDays == minDays
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
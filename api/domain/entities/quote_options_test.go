@@ -0,0 +1,103 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func newOptionsTestResponse() domain.QuoteResponse {
+	return domain.QuoteResponse{
+		Carriers: []domain.Carrier{
+			{Name: "EXPRESSO FR", Service: "Rodoviário", Price: 17.0, DeadlineDays: 3, Details: &domain.CarrierDetails{Modal: "Rodoviário"}},
+			{Name: "Correios", Service: "SEDEX", Price: 20.99, DeadlineDays: 1, Details: &domain.CarrierDetails{Modal: "Aéreo"}},
+			{Name: "Jadlog", Service: ".Package", Price: 17.0, DeadlineDays: 5, Details: &domain.CarrierDetails{Modal: "Rodoviário"}},
+		},
+	}
+}
+
+func carrierNames(response domain.QuoteResponse) []string {
+	names := make([]string, 0, len(response.Carriers))
+	for _, carrier := range response.Carriers {
+		names = append(names, carrier.Name)
+	}
+	return names
+}
+
+func TestQuoteResponse_ApplyOptionsFlags(t *testing.T) {
+	response := newOptionsTestResponse()
+
+	result := response.ApplyOptions(domain.QuoteOptions{})
+
+	// Upstream order is kept and ties are all flagged
+	assert.Equal(t, []string{"EXPRESSO FR", "Correios", "Jadlog"}, carrierNames(result))
+	assert.True(t, result.Carriers[0].Cheapest)
+	assert.False(t, result.Carriers[0].Fastest)
+	assert.False(t, result.Carriers[1].Cheapest)
+	assert.True(t, result.Carriers[1].Fastest)
+	assert.True(t, result.Carriers[2].Cheapest)
+
+	// The original response is not modified
+	assert.False(t, response.Carriers[0].Cheapest)
+}
+
+func TestQuoteResponse_ApplyOptionsSort(t *testing.T) {
+	response := newOptionsTestResponse()
+
+	byPrice := response.ApplyOptions(domain.QuoteOptions{Sort: domain.SortByPrice})
+	assert.Equal(t, []string{"EXPRESSO FR", "Jadlog", "Correios"}, carrierNames(byPrice))
+
+	byDeadline := response.ApplyOptions(domain.QuoteOptions{Sort: domain.SortByDeadline})
+	assert.Equal(t, []string{"Correios", "EXPRESSO FR", "Jadlog"}, carrierNames(byDeadline))
+}
+
+func TestQuoteResponse_ApplyOptionsFilters(t *testing.T) {
+	response := newOptionsTestResponse()
+
+	tests := []struct {
+		name     string
+		options  domain.QuoteOptions
+		expected []string
+	}{
+		{"max price", domain.QuoteOptions{MaxPrice: 18}, []string{"EXPRESSO FR", "Jadlog"}},
+		{"max days", domain.QuoteOptions{MaxDays: 3}, []string{"EXPRESSO FR", "Correios"}},
+		{"allowed carriers", domain.QuoteOptions{Carriers: []string{"correios", " jadlog "}}, []string{"Correios", "Jadlog"}},
+		{"excluded carriers", domain.QuoteOptions{ExcludeCarriers: []string{"Expresso FR"}}, []string{"Correios", "Jadlog"}},
+		{"modal", domain.QuoteOptions{Modal: "aéreo"}, []string{"Correios"}},
+		{"nothing left", domain.QuoteOptions{MaxPrice: 1}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, carrierNames(response.ApplyOptions(tt.options)))
+		})
+	}
+
+	// Flags are computed over the filtered offers
+	result := response.ApplyOptions(domain.QuoteOptions{ExcludeCarriers: []string{"Correios"}})
+	assert.True(t, result.Carriers[0].Fastest)
+	assert.False(t, result.Carriers[1].Fastest)
+}
+
+func TestQuoteOptions_Validate(t *testing.T) {
+	assert.NoError(t, domain.QuoteOptions{Sort: domain.SortByPrice, MaxPrice: 10, MaxDays: 2}.Validate())
+
+	err := domain.QuoteOptions{
+		Sort:            "rating",
+		MaxPrice:        -1,
+		MaxDays:         -1,
+		Carriers:        []string{"Correios"},
+		ExcludeCarriers: []string{"correios"},
+	}.Validate()
+
+	assert.True(t, errors.Is(err, domain.ErrValidationFailed))
+	var validationErr *domain.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	fields := make([]string, 0, len(validationErr.Errors))
+	for _, inputErr := range validationErr.Errors {
+		fields = append(fields, inputErr.Field)
+	}
+	assert.Equal(t, []string{"sort", "max_price", "max_days", "carriers"}, fields)
+}
@@ -1,7 +1,9 @@
 package domain_test
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
@@ -81,3 +83,82 @@ func TestQuoteResponse(t *testing.T) {
 	assert.Equal(t, "1", response.Carriers[1].Deadline)
 	assert.Equal(t, 20.99, response.Carriers[1].Price)
 }
+
+func TestQuoteResponse_WithoutDetails(t *testing.T) {
+	response := domain.QuoteResponse{
+		Carriers: []domain.Carrier{
+			{
+				Name:         "EXPRESSO FR",
+				Service:      "Rodoviário",
+				Deadline:     "3",
+				Price:        17.0,
+				DeadlineDays: 3,
+				Details: &domain.CarrierDetails{
+					RegisteredNumber: "25438296000158",
+					CostPrice:        15.5,
+					FinalPrice:       17.0,
+					Modal:            "Rodoviário",
+					Expiration:       time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	summary := response.WithoutDetails()
+
+	// The summary keeps the original JSON shape plus the numeric deadline
+	data, err := json.Marshal(summary.Carriers[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"EXPRESSO FR","service":"Rodoviário","deadline":"3","price":17,"deadline_days":3}`, string(data))
+
+	// The original response is not modified
+	assert.NotNil(t, response.Carriers[0].Details)
+}
+
+func TestCarriersJSON_PersistsDetails(t *testing.T) {
+	carriers := domain.CarriersJSON{
+		{
+			Name:  "EXPRESSO FR",
+			Price: 17.0,
+			Details: &domain.CarrierDetails{
+				Logo:         "https://example.com/expresso.png",
+				Weights:      domain.CarrierWeights{Real: 5.0, Cubed: 2.4, Used: 5.0},
+				HomeDelivery: true,
+			},
+		},
+	}
+
+	value, err := carriers.Value()
+	assert.NoError(t, err)
+
+	var scanned domain.CarriersJSON
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, carriers, scanned)
+}
+
+func TestQuoteResponse_GroupByDispatcher(t *testing.T) {
+	warehouseA := &domain.CarrierDispatcher{ID: "d1", RegisteredNumber: "25438296000158", Zipcode: "29161376"}
+	warehouseB := &domain.CarrierDispatcher{ID: "d2", RegisteredNumber: "11222333000181", Zipcode: "01311000"}
+
+	response := domain.QuoteResponse{
+		Carriers: []domain.Carrier{
+			{Name: "Correios", Price: 20, Dispatcher: warehouseA},
+			{Name: "Jadlog", Price: 12, Dispatcher: warehouseB},
+			{Name: "EXPRESSO FR", Price: 17, Dispatcher: warehouseA},
+		},
+	}
+
+	grouped := response.GroupByDispatcher()
+
+	// The flat list is kept and the groups follow the order of appearance
+	assert.Len(t, grouped.Carriers, 3)
+	assert.Len(t, grouped.Dispatchers, 2)
+	assert.Equal(t, *warehouseA, grouped.Dispatchers[0].CarrierDispatcher)
+	assert.Equal(t, []string{"Correios", "EXPRESSO FR"}, []string{grouped.Dispatchers[0].Carriers[0].Name, grouped.Dispatchers[0].Carriers[1].Name})
+	assert.Equal(t, *warehouseB, grouped.Dispatchers[1].CarrierDispatcher)
+	assert.Len(t, grouped.Dispatchers[1].Carriers, 1)
+
+	data, err := json.Marshal(grouped.Dispatchers[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"d2","registered_number":"11222333000181","zipcode":"01311000","carrier":[{"name":"Jadlog","service":"","deadline":"","price":12,"deadline_days":0,"dispatcher":{"id":"d2","registered_number":"11222333000181","zipcode":"01311000"}}]}`, string(data))
+}
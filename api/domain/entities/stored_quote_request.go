@@ -0,0 +1,135 @@
+package domain
+
+import "time"
+
+// QuoteTotals resume a carga de uma solicitação de cotação
+// @Description Totais da carga cotada
+type QuoteTotals struct {
+	// Quantidade total de itens
+	// @example 3
+	VolumeCount int `json:"volume_count" gorm:"column:volume_count"`
+	// Peso total em kg
+	// @example 13.0
+	TotalWeight float64 `json:"total_weight" gorm:"column:total_weight"`
+	// Valor total da mercadoria
+	// @example 1461.0
+	TotalValue float64 `json:"total_value" gorm:"column:total_value"`
+	// Cubagem total em m³
+	// @example 0.08
+	TotalCubage float64 `json:"total_cubage" gorm:"column:total_cubage"`
+}
+
+// Totals soma quantidade, peso, valor e cubagem de todos os volumes, inclusive os dos dispatchers
+func (r QuoteRequest) Totals() QuoteTotals {
+	var totals QuoteTotals
+	add := func(volumes []Volume) {
+		for _, v := range volumes {
+			amount := float64(v.Amount)
+			totals.VolumeCount += v.Amount
+			totals.TotalWeight += amount * v.UnitaryWeight
+			totals.TotalValue += amount * v.Price
+			totals.TotalCubage += amount * v.Height * v.Width * v.Length
+		}
+	}
+
+	add(r.Volumes)
+	for _, dispatcher := range r.Dispatchers {
+		add(dispatcher.Volumes)
+	}
+	return totals
+}
+
+// StoredQuoteRequest é a solicitação de cotação persistida junto à resposta
+// @Description Solicitação que originou uma cotação armazenada
+type StoredQuoteRequest struct {
+	// @example 42
+	ID uint `json:"id" gorm:"primaryKey"`
+	// Cotação gerada por esta solicitação
+	// @example 42
+	QuoteResponseID uint `json:"quote_id" gorm:"not null;uniqueIndex"`
+	// CEP do destinatário
+	// @example "01311000"
+	RecipientZipcode string `json:"recipient_zipcode" gorm:"type:varchar(8);not null;index"`
+	// Tipo do destinatário: 0 = pessoa física, 1 = pessoa jurídica
+	// @example 0
+	RecipientType int `json:"recipient_type" gorm:"not null;default:0"`
+	// CPF ou CNPJ do destinatário, sem pontuação
+	// @example "11222333000181"
+	RecipientRegisteredNumber string `json:"recipient_registered_number,omitempty" gorm:"type:varchar(14)"`
+	QuoteTotals
+	// Volumes da solicitação
+	Volumes   []StoredQuoteVolume `json:"volumes" gorm:"foreignKey:QuoteRequestID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func (StoredQuoteRequest) TableName() string {
+	return "quote_requests"
+}
+
+// StoredQuoteVolume é um volume de uma solicitação persistida
+// @Description Volume de uma solicitação de cotação armazenada
+type StoredQuoteVolume struct {
+	ID             uint `json:"-" gorm:"primaryKey"`
+	QuoteRequestID uint `json:"-" gorm:"not null;index"`
+	// CNPJ do centro de distribuição, vazio para o padrão
+	// @example "25438296000158"
+	DispatcherRegisteredNumber string `json:"dispatcher_registered_number,omitempty" gorm:"type:varchar(14)"`
+	// CEP de origem, vazio para o padrão
+	// @example "29161376"
+	DispatcherZipcode string `json:"dispatcher_zipcode,omitempty" gorm:"type:varchar(8)"`
+	// @example 7
+	Category int `json:"category"`
+	// @example 1
+	Amount int `json:"amount"`
+	// @example 5.0
+	UnitaryWeight float64 `json:"unitary_weight"`
+	// @example 349.90
+	Price float64 `json:"price"`
+	// @example "abc-teste-123"
+	SKU string `json:"sku"`
+	// @example 0.2
+	Height float64 `json:"height"`
+	// @example 0.2
+	Width float64 `json:"width"`
+	// @example 0.2
+	Length float64 `json:"length"`
+}
+
+func (StoredQuoteVolume) TableName() string {
+	return "quote_request_volumes"
+}
+
+// NewStoredQuoteRequest normaliza a solicitação para persistência, calculando os totais
+func NewStoredQuoteRequest(request QuoteRequest) StoredQuoteRequest {
+	registeredNumber, _ := request.RecipientDocuments()
+	stored := StoredQuoteRequest{
+		RecipientZipcode:          request.Recipient.Address.Zipcode,
+		RecipientType:             request.Recipient.Type,
+		RecipientRegisteredNumber: registeredNumber,
+		QuoteTotals:               request.Totals(),
+		Volumes:                   []StoredQuoteVolume{},
+	}
+
+	add := func(dispatcher Dispatcher) {
+		for _, v := range dispatcher.Volumes {
+			stored.Volumes = append(stored.Volumes, StoredQuoteVolume{
+				DispatcherRegisteredNumber: dispatcher.RegisteredNumber,
+				DispatcherZipcode:          dispatcher.Zipcode,
+				Category:                   v.Category,
+				Amount:                     v.Amount,
+				UnitaryWeight:              v.UnitaryWeight,
+				Price:                      v.Price,
+				SKU:                        v.SKU,
+				Height:                     v.Height,
+				Width:                      v.Width,
+				Length:                     v.Length,
+			})
+		}
+	}
+
+	add(Dispatcher{Volumes: request.Volumes})
+	for _, dispatcher := range request.Dispatchers {
+		add(dispatcher)
+	}
+	return stored
+}
@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestQuoteRequest_Totals(t *testing.T) {
+	request := domain.QuoteRequest{}
+	request.Volumes = []domain.Volume{
+		{Amount: 1, UnitaryWeight: 5, Price: 349, Height: 0.2, Width: 0.2, Length: 0.2},
+	}
+	request.Dispatchers = []domain.Dispatcher{
+		{Zipcode: "80010000", Volumes: []domain.Volume{{Amount: 2, UnitaryWeight: 4, Price: 556, Height: 0.4, Width: 0.6, Length: 0.15}}},
+	}
+
+	totals := request.Totals()
+
+	assert.Equal(t, 3, totals.VolumeCount)
+	assert.InDelta(t, 13.0, totals.TotalWeight, 1e-9)
+	assert.InDelta(t, 1461.0, totals.TotalValue, 1e-9)
+	assert.InDelta(t, 0.008+0.072, totals.TotalCubage, 1e-9)
+}
+
+func TestNewStoredQuoteRequest(t *testing.T) {
+	request := domain.QuoteRequest{}
+	request.Recipient.Type = domain.RecipientTypeCompany
+	request.Recipient.RegisteredNumber = "11.222.333/0001-81"
+	request.Recipient.Address.Zipcode = "01311000"
+	request.Volumes = []domain.Volume{{Category: 7, Amount: 1, UnitaryWeight: 5, Price: 349, SKU: "abc-teste-123"}}
+	request.Dispatchers = []domain.Dispatcher{
+		{RegisteredNumber: "25438296000158", Zipcode: "80010000", Volumes: []domain.Volume{{Category: 7, Amount: 2, UnitaryWeight: 4, Price: 556}}},
+	}
+
+	stored := domain.NewStoredQuoteRequest(request)
+
+	assert.Equal(t, "01311000", stored.RecipientZipcode)
+	assert.Equal(t, domain.RecipientTypeCompany, stored.RecipientType)
+	assert.Equal(t, "11222333000181", stored.RecipientRegisteredNumber)
+	assert.Equal(t, request.Totals(), stored.QuoteTotals)
+
+	// Default volumes have no dispatcher, dispatcher volumes keep their origin
+	assert.Len(t, stored.Volumes, 2)
+	assert.Empty(t, stored.Volumes[0].DispatcherZipcode)
+	assert.Equal(t, "abc-teste-123", stored.Volumes[0].SKU)
+	assert.Equal(t, "25438296000158", stored.Volumes[1].DispatcherRegisteredNumber)
+	assert.Equal(t, "80010000", stored.Volumes[1].DispatcherZipcode)
+	assert.Equal(t, 2, stored.Volumes[1].Amount)
+}
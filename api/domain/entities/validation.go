@@ -0,0 +1,195 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Limites aceitos para uma solicitação de cotação
+const (
+	MaxVolumes       = 100
+	MaxDispatchers   = 10
+	MinCategory      = 1
+	MaxCategory      = 999
+	MaxAmount        = 10000
+	MaxUnitaryWeight = 10000.0   // kg
+	MaxPrice         = 1000000.0 // R$
+	MaxDimension     = 30.0      // metros
+)
+
+var (
+	zipcodePattern          = regexp.MustCompile(`^[0-9]{8}$`)
+	registeredNumberPattern = regexp.MustCompile(`^[0-9]{14}$`)
+	stateInscriptionPattern = regexp.MustCompile(`^([0-9]{2,14}|ISENTO)$`)
+)
+
+// InputError descreve uma violação de regra em um campo da requisição
+// @Description Erro de validação de um campo da requisição
+type InputError struct {
+	// Caminho do campo inválido
+	// @example "volumes[0].unitary_weight"
+	Field string `json:"field"`
+	// Descrição da violação
+	// @example "must be greater than 0"
+	Message string `json:"message"`
+}
+
+func (e *InputError) Error() string {
+	return e.Message + " for field " + e.Field
+}
+
+// ValidationError agrupa todas as violações encontradas em uma requisição
+type ValidationError struct {
+	Errors []InputError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, inputErr := range e.Errors {
+		messages = append(messages, inputErr.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Is faz com que errors.Is(err, ErrValidationFailed) reconheça o erro de validação
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+func (e *ValidationError) add(field, message string) {
+	e.Errors = append(e.Errors, InputError{Field: field, Message: message})
+}
+
+// Validate verifica a solicitação de cotação e retorna todas as violações de uma vez,
+// ou nil quando a solicitação é válida
+func (r QuoteRequest) Validate() error {
+	validationErr := &ValidationError{}
+
+	zipcode := r.Recipient.Address.Zipcode
+	switch {
+	case zipcode == "":
+		validationErr.add("recipient.address.zipcode", "Zipcode cannot be empty")
+	case !zipcodePattern.MatchString(zipcode):
+		validationErr.add("recipient.address.zipcode", "Zipcode must have exactly 8 digits")
+	}
+
+	r.validateRecipientDocuments(validationErr)
+
+	totalVolumes := len(r.Volumes)
+	for _, dispatcher := range r.Dispatchers {
+		totalVolumes += len(dispatcher.Volumes)
+	}
+
+	switch {
+	case len(r.Volumes) == 0 && len(r.Dispatchers) == 0:
+		validationErr.add("volumes", "At least one volume is required")
+	case totalVolumes > MaxVolumes:
+		validationErr.add("volumes", fmt.Sprintf("At most %d volumes are allowed", MaxVolumes))
+	}
+
+	for i, volume := range r.Volumes {
+		volume.validate(fmt.Sprintf("volumes[%d]", i), validationErr)
+	}
+
+	if len(r.Dispatchers) > MaxDispatchers {
+		validationErr.add("dispatchers", fmt.Sprintf("At most %d dispatchers are allowed", MaxDispatchers))
+	}
+
+	for i, dispatcher := range r.Dispatchers {
+		dispatcher.validate(fmt.Sprintf("dispatchers[%d]", i), validationErr)
+	}
+
+	seen := map[int]bool{}
+	for i, simulationType := range r.SimulationType {
+		field := fmt.Sprintf("simulation_type[%d]", i)
+		switch {
+		case simulationType != SimulationTypeFractional && simulationType != SimulationTypeFullLoad:
+			validationErr.add(field, fmt.Sprintf("Simulation type must be %d (fractional) or %d (full load)", SimulationTypeFractional, SimulationTypeFullLoad))
+		case seen[simulationType]:
+			validationErr.add(field, "Simulation type must not be repeated")
+		}
+		seen[simulationType] = true
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+func (r QuoteRequest) validateRecipientDocuments(validationErr *ValidationError) {
+	recipient := r.Recipient
+	registeredNumber := digitsOnly(recipient.RegisteredNumber)
+
+	switch recipient.Type {
+	case RecipientTypeIndividual:
+		if recipient.RegisteredNumber != "" && !ValidCPF(registeredNumber) {
+			validationErr.add("recipient.registered_number", "Registered number must be a valid CPF for individual recipients")
+		}
+		if recipient.StateInscription != "" {
+			validationErr.add("recipient.state_inscription", "State inscription is only allowed for company recipients")
+		}
+	case RecipientTypeCompany:
+		switch {
+		case recipient.RegisteredNumber == "":
+			validationErr.add("recipient.registered_number", "Registered number is required for company recipients")
+		case !ValidCNPJ(registeredNumber):
+			validationErr.add("recipient.registered_number", "Registered number must be a valid CNPJ for company recipients")
+		}
+		if recipient.StateInscription != "" && !stateInscriptionPattern.MatchString(normalizeStateInscription(recipient.StateInscription)) {
+			validationErr.add("recipient.state_inscription", "State inscription must have up to 14 digits or be ISENTO")
+		}
+	default:
+		validationErr.add("recipient.type", fmt.Sprintf("Type must be %d (individual) or %d (company)", RecipientTypeIndividual, RecipientTypeCompany))
+	}
+}
+
+// normalizeStateInscription remove a pontuação da inscrição estadual, mantendo "ISENTO"
+func normalizeStateInscription(stateInscription string) string {
+	if strings.EqualFold(strings.TrimSpace(stateInscription), "ISENTO") {
+		return "ISENTO"
+	}
+	return digitsOnly(stateInscription)
+}
+
+func (d Dispatcher) validate(prefix string, validationErr *ValidationError) {
+	if d.RegisteredNumber != "" && !registeredNumberPattern.MatchString(d.RegisteredNumber) {
+		validationErr.add(prefix+".registered_number", "Registered number must have exactly 14 digits")
+	}
+	if d.Zipcode != "" && !zipcodePattern.MatchString(d.Zipcode) {
+		validationErr.add(prefix+".zipcode", "Zipcode must have exactly 8 digits")
+	}
+	if len(d.Volumes) == 0 {
+		validationErr.add(prefix+".volumes", "At least one volume is required")
+	}
+	for i, volume := range d.Volumes {
+		volume.validate(fmt.Sprintf("%s.volumes[%d]", prefix, i), validationErr)
+	}
+}
+
+func (v Volume) validate(prefix string, validationErr *ValidationError) {
+	if v.Category < MinCategory || v.Category > MaxCategory {
+		validationErr.add(prefix+".category", fmt.Sprintf("Category must be between %d and %d", MinCategory, MaxCategory))
+	}
+
+	if v.Amount <= 0 {
+		validationErr.add(prefix+".amount", "Amount must be greater than 0")
+	} else if v.Amount > MaxAmount {
+		validationErr.add(prefix+".amount", fmt.Sprintf("Amount must be at most %d", MaxAmount))
+	}
+
+	validatePositive(prefix+".unitary_weight", "Unitary weight", v.UnitaryWeight, MaxUnitaryWeight, validationErr)
+	validatePositive(prefix+".price", "Price", v.Price, MaxPrice, validationErr)
+	validatePositive(prefix+".height", "Height", v.Height, MaxDimension, validationErr)
+	validatePositive(prefix+".width", "Width", v.Width, MaxDimension, validationErr)
+	validatePositive(prefix+".length", "Length", v.Length, MaxDimension, validationErr)
+}
+
+func validatePositive(field, label string, value, max float64, validationErr *ValidationError) {
+	if value <= 0 {
+		validationErr.add(field, label+" must be greater than 0")
+	} else if value > max {
+		validationErr.add(field, fmt.Sprintf("%s must be at most %g", label, max))
+	}
+}
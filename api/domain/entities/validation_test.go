@@ -0,0 +1,183 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func newValidQuoteRequest() domain.QuoteRequest {
+	request := domain.QuoteRequest{}
+	request.Recipient.Address.Zipcode = "01311000"
+	request.Volumes = []domain.Volume{
+		{
+			Category:      7,
+			Amount:        1,
+			UnitaryWeight: 5.0,
+			Price:         349.0,
+			SKU:           "abc-teste-123",
+			Height:        0.2,
+			Width:         0.2,
+			Length:        0.2,
+		},
+	}
+	return request
+}
+
+func validationFields(t *testing.T, err error) []string {
+	validationErr, ok := err.(*domain.ValidationError)
+	if !assert.True(t, ok, "expected *domain.ValidationError, got %T", err) {
+		return nil
+	}
+
+	fields := make([]string, 0, len(validationErr.Errors))
+	for _, inputErr := range validationErr.Errors {
+		fields = append(fields, inputErr.Field)
+	}
+	return fields
+}
+
+func TestQuoteRequest_ValidateValid(t *testing.T) {
+	assert.NoError(t, newValidQuoteRequest().Validate())
+}
+
+func TestQuoteRequest_ValidateZipcode(t *testing.T) {
+	for _, zipcode := range []string{"", "0131100", "013110000", "01311-000", "abcdefgh"} {
+		request := newValidQuoteRequest()
+		request.Recipient.Address.Zipcode = zipcode
+
+		assert.Equal(t, []string{"recipient.address.zipcode"}, validationFields(t, request.Validate()), "zipcode %q", zipcode)
+	}
+}
+
+func TestQuoteRequest_ValidateVolumes(t *testing.T) {
+	request := newValidQuoteRequest()
+	request.Volumes = nil
+
+	assert.Equal(t, []string{"volumes"}, validationFields(t, request.Validate()))
+
+	request.Volumes = make([]domain.Volume, domain.MaxVolumes+1)
+	for i := range request.Volumes {
+		request.Volumes[i] = newValidQuoteRequest().Volumes[0]
+	}
+
+	assert.Equal(t, []string{"volumes"}, validationFields(t, request.Validate()))
+}
+
+func TestQuoteRequest_ValidateReportsEveryViolation(t *testing.T) {
+	request := newValidQuoteRequest()
+	request.Recipient.Address.Zipcode = "123"
+	request.Volumes = append(request.Volumes, domain.Volume{
+		Category:      0,
+		Amount:        -1,
+		UnitaryWeight: -5.0,
+		Price:         0,
+		Height:        0,
+		Width:         domain.MaxDimension + 1,
+		Length:        -0.1,
+	})
+
+	err := request.Validate()
+
+	assert.Equal(t, []string{
+		"recipient.address.zipcode",
+		"volumes[1].category",
+		"volumes[1].amount",
+		"volumes[1].unitary_weight",
+		"volumes[1].price",
+		"volumes[1].height",
+		"volumes[1].width",
+		"volumes[1].length",
+	}, validationFields(t, err))
+	assert.Contains(t, err.Error(), "Width must be at most 30 for field volumes[1].width")
+}
+
+func TestQuoteRequest_ValidateMaximums(t *testing.T) {
+	request := newValidQuoteRequest()
+	request.Volumes[0].Category = domain.MaxCategory + 1
+	request.Volumes[0].Amount = domain.MaxAmount + 1
+	request.Volumes[0].UnitaryWeight = domain.MaxUnitaryWeight + 1
+	request.Volumes[0].Price = domain.MaxPrice + 1
+
+	assert.Equal(t, []string{
+		"volumes[0].category",
+		"volumes[0].amount",
+		"volumes[0].unitary_weight",
+		"volumes[0].price",
+	}, validationFields(t, request.Validate()))
+}
+
+func TestQuoteRequest_ValidateDispatchers(t *testing.T) {
+	volume := newValidQuoteRequest().Volumes[0]
+
+	// Dispatchers alone are enough, and their fields are optional
+	request := newValidQuoteRequest()
+	request.Volumes = nil
+	request.Dispatchers = []domain.Dispatcher{
+		{RegisteredNumber: "11222333000181", Zipcode: "01311000", Volumes: []domain.Volume{volume}},
+		{Volumes: []domain.Volume{volume}},
+	}
+	assert.NoError(t, request.Validate())
+
+	invalid := volume
+	invalid.Amount = 0
+	request.Dispatchers = []domain.Dispatcher{
+		{RegisteredNumber: "123", Zipcode: "1311", Volumes: []domain.Volume{invalid}},
+		{},
+	}
+
+	assert.Equal(t, []string{
+		"dispatchers[0].registered_number",
+		"dispatchers[0].zipcode",
+		"dispatchers[0].volumes[0].amount",
+		"dispatchers[1].volumes",
+	}, validationFields(t, request.Validate()))
+}
+
+func TestQuoteRequest_ValidateSimulationType(t *testing.T) {
+	request := newValidQuoteRequest()
+	request.SimulationType = []int{domain.SimulationTypeFractional, domain.SimulationTypeFullLoad}
+	assert.NoError(t, request.Validate())
+
+	request.SimulationType = []int{2, domain.SimulationTypeFullLoad, domain.SimulationTypeFullLoad}
+	assert.Equal(t, []string{"simulation_type[0]", "simulation_type[2]"}, validationFields(t, request.Validate()))
+}
+
+func TestQuoteRequest_ValidateRecipientDocuments(t *testing.T) {
+	tests := []struct {
+		name             string
+		recipientType    int
+		registeredNumber string
+		stateInscription string
+		expected         []string
+	}{
+		{"individual without document", domain.RecipientTypeIndividual, "", "", nil},
+		{"individual with CPF", domain.RecipientTypeIndividual, "529.982.247-25", "", nil},
+		{"individual with invalid CPF", domain.RecipientTypeIndividual, "52998224724", "", []string{"recipient.registered_number"}},
+		{"individual with state inscription", domain.RecipientTypeIndividual, "", "123456789", []string{"recipient.state_inscription"}},
+		{"company with CNPJ and inscription", domain.RecipientTypeCompany, "11.222.333/0001-81", "123.456.789", nil},
+		{"company exempt from inscription", domain.RecipientTypeCompany, "11222333000181", "ISENTO", nil},
+		{"company without CNPJ", domain.RecipientTypeCompany, "", "", []string{"recipient.registered_number"}},
+		{"company with CPF", domain.RecipientTypeCompany, "52998224725", "", []string{"recipient.registered_number"}},
+		{"company with invalid inscription", domain.RecipientTypeCompany, "11222333000181", "ABC", []string{"recipient.state_inscription"}},
+		{"unknown type", 2, "", "", []string{"recipient.type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := newValidQuoteRequest()
+			request.Recipient.Type = tt.recipientType
+			request.Recipient.RegisteredNumber = tt.registeredNumber
+			request.Recipient.StateInscription = tt.stateInscription
+
+			err := request.Validate()
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.expected, validationFields(t, err))
+		})
+	}
+}
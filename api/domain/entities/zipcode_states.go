@@ -0,0 +1,60 @@
+package domain
+
+// ZipcodeRange é uma faixa de CEPs, pelos 5 primeiros dígitos, atribuída a uma UF
+type ZipcodeRange struct {
+	// Primeiro prefixo da faixa (inclusivo)
+	First string
+	// Último prefixo da faixa (inclusivo)
+	Last string
+	// Sigla da UF
+	State string
+}
+
+// StateZipcodeRanges são as faixas de CEP de cada UF definidas pelos Correios
+var StateZipcodeRanges = []ZipcodeRange{
+	{First: "01000", Last: "19999", State: "SP"},
+	{First: "20000", Last: "28999", State: "RJ"},
+	{First: "29000", Last: "29999", State: "ES"},
+	{First: "30000", Last: "39999", State: "MG"},
+	{First: "40000", Last: "48999", State: "BA"},
+	{First: "49000", Last: "49999", State: "SE"},
+	{First: "50000", Last: "56999", State: "PE"},
+	{First: "57000", Last: "57999", State: "AL"},
+	{First: "58000", Last: "58999", State: "PB"},
+	{First: "59000", Last: "59999", State: "RN"},
+	{First: "60000", Last: "63999", State: "CE"},
+	{First: "64000", Last: "64999", State: "PI"},
+	{First: "65000", Last: "65999", State: "MA"},
+	{First: "66000", Last: "68899", State: "PA"},
+	{First: "68900", Last: "68999", State: "AP"},
+	{First: "69000", Last: "69299", State: "AM"},
+	{First: "69300", Last: "69399", State: "RR"},
+	{First: "69400", Last: "69899", State: "AM"},
+	{First: "69900", Last: "69999", State: "AC"},
+	{First: "70000", Last: "72799", State: "DF"},
+	{First: "72800", Last: "72999", State: "GO"},
+	{First: "73000", Last: "73699", State: "DF"},
+	{First: "73700", Last: "76799", State: "GO"},
+	{First: "76800", Last: "76999", State: "RO"},
+	{First: "77000", Last: "77999", State: "TO"},
+	{First: "78000", Last: "78899", State: "MT"},
+	{First: "79000", Last: "79999", State: "MS"},
+	{First: "80000", Last: "87999", State: "PR"},
+	{First: "88000", Last: "89999", State: "SC"},
+	{First: "90000", Last: "99999", State: "RS"},
+}
+
+// StateForZipcode retorna a UF do CEP, ou vazio quando ele não pertence a nenhuma faixa
+func StateForZipcode(zipcode string) string {
+	zipcode = digitsOnly(zipcode)
+	if len(zipcode) < 5 {
+		return ""
+	}
+	prefix := zipcode[:5]
+	for _, r := range StateZipcodeRanges {
+		if prefix >= r.First && prefix <= r.Last {
+			return r.State
+		}
+	}
+	return ""
+}
@@ -0,0 +1,33 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestStateForZipcode(t *testing.T) {
+	cases := map[string]string{
+		"01311000":  "SP",
+		"29161-376": "ES",
+		"40010000":  "BA",
+		"69301000":  "RR",
+		"69400000":  "AM",
+		"72850000":  "GO",
+		"73000000":  "DF",
+		"99999999":  "RS",
+		"00999999":  "",
+		"123":       "",
+	}
+	for zipcode, state := range cases {
+		assert.Equal(t, state, domain.StateForZipcode(zipcode), zipcode)
+	}
+}
+
+func TestStateZipcodeRanges_Contiguous(t *testing.T) {
+	// Ranges are sorted and do not overlap, so a CASE over them is unambiguous
+	for i := 1; i < len(domain.StateZipcodeRanges); i++ {
+		assert.Less(t, domain.StateZipcodeRanges[i-1].Last, domain.StateZipcodeRanges[i].First)
+	}
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCache is a mock implementation of the Cache interface
+type MockCache struct {
+	mock.Mock
+}
+
+// SetCacheJSON is a mock implementation of the SetCacheJSON method
+func (m *MockCache) SetCacheJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	args := m.Called(ctx, key, value, expiration)
+	return args.Error(0)
+}
+
+// GetCacheJSON is a mock implementation of the GetCacheJSON method.
+// When the first return value is a func(dest interface{}), it is called to fill dest.
+func (m *MockCache) GetCacheJSON(ctx context.Context, key string, dest interface{}) error {
+	args := m.Called(ctx, key, dest)
+
+	if fill, ok := args.Get(0).(func(dest interface{})); ok {
+		fill(dest)
+	}
+
+	return args.Error(1)
+}
+
+// IncrementCache is a mock implementation of the IncrementCache method
+func (m *MockCache) IncrementCache(ctx context.Context, key string) (int64, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -13,8 +13,8 @@ type MockMetricsRepository struct {
 }
 
 // GetMetrics is a mock implementation of the GetMetrics method
-func (m *MockMetricsRepository) GetMetrics(ctx context.Context, lastQuotes int) (*domain.MetricsResponse, error) {
-	args := m.Called(ctx, lastQuotes)
+func (m *MockMetricsRepository) GetMetrics(ctx context.Context, query domain.MetricsQuery) (*domain.MetricsResponse, error) {
+	args := m.Called(ctx, query)
 
 	// If the return value is nil, return nil to avoid casting nil to *domain.MetricsResponse
 	if args.Get(0) == nil {
@@ -23,3 +23,14 @@ func (m *MockMetricsRepository) GetMetrics(ctx context.Context, lastQuotes int)
 
 	return args.Get(0).(*domain.MetricsResponse), args.Error(1)
 }
+
+// GetMetricsSeries is a mock implementation of the GetMetricsSeries method
+func (m *MockMetricsRepository) GetMetricsSeries(ctx context.Context, query domain.MetricsSeriesQuery) (*domain.MetricsSeries, error) {
+	args := m.Called(ctx, query)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.MetricsSeries), args.Error(1)
+}
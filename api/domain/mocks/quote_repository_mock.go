@@ -13,8 +13,8 @@ type MockQuoteRepository struct {
 }
 
 // SaveQuote is a mock implementation of the SaveQuote method
-func (m *MockQuoteRepository) SaveQuote(ctx context.Context, quote *domain.QuoteResponse) error {
-	args := m.Called(ctx, quote)
+func (m *MockQuoteRepository) SaveQuote(ctx context.Context, quote *domain.QuoteResponse, request domain.QuoteRequest) error {
+	args := m.Called(ctx, quote, request)
 	return args.Error(0)
 }
 
@@ -29,3 +29,52 @@ func (m *MockQuoteRepository) GetLastQuotes(ctx context.Context, limit int) ([]d
 
 	return args.Get(0).([]domain.QuoteResponse), args.Error(1)
 }
+
+// ListQuotes is a mock implementation of the ListQuotes method
+func (m *MockQuoteRepository) ListQuotes(ctx context.Context, filter domain.QuoteFilter) ([]domain.QuoteResponse, error) {
+	args := m.Called(ctx, filter)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain.QuoteResponse), args.Error(1)
+}
+
+// GetQuote is a mock implementation of the GetQuote method
+func (m *MockQuoteRepository) GetQuote(ctx context.Context, id uint) (*domain.QuoteResponse, error) {
+	args := m.Called(ctx, id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.QuoteResponse), args.Error(1)
+}
+
+// GetQuoteRequest is a mock implementation of the GetQuoteRequest method
+func (m *MockQuoteRepository) GetQuoteRequest(ctx context.Context, quoteID uint) (*domain.StoredQuoteRequest, error) {
+	args := m.Called(ctx, quoteID)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.StoredQuoteRequest), args.Error(1)
+}
+
+// ExportQuotes is a mock implementation of the ExportQuotes method.
+// When the first return value is a []domain.QuoteResponse, each quote is passed to fn.
+func (m *MockQuoteRepository) ExportQuotes(ctx context.Context, filter domain.QuoteExportFilter, fn func(domain.QuoteResponse) error) error {
+	args := m.Called(ctx, filter)
+
+	if quotes, ok := args.Get(0).([]domain.QuoteResponse); ok {
+		for _, quote := range quotes {
+			if err := fn(quote); err != nil {
+				return err
+			}
+		}
+	}
+
+	return args.Error(1)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// MockShippingProvider is a mock implementation of the ShippingProvider interface
+type MockShippingProvider struct {
+	mock.Mock
+}
+
+// Quote is a mock implementation of the Quote method
+func (m *MockShippingProvider) Quote(ctx context.Context, request domain.QuoteRequest) (*domain.QuoteResponse, error) {
+	args := m.Called(ctx, request)
+
+	// If the return value is nil, return nil to avoid casting nil to *domain.QuoteResponse
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.QuoteResponse), args.Error(1)
+}
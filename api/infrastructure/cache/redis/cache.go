@@ -11,6 +11,7 @@ type Cache interface {
 	GetCache(ctx context.Context, key string) (string, error)
 	DeleteCache(ctx context.Context, key string) error
 	ExistsCache(ctx context.Context, key string) (bool, error)
+	IncrementCache(ctx context.Context, key string) (int64, error)
 	SetCacheJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	GetCacheJSON(ctx context.Context, key string, dest interface{}) error
 }
@@ -41,6 +42,10 @@ func (rc *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	return rc.client.ExistsCache(ctx, key)
 }
 
+func (rc *RedisCache) Increment(ctx context.Context, key string) (int64, error) {
+	return rc.client.IncrementCache(ctx, key)
+}
+
 func (rc *RedisCache) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	return rc.client.SetCacheJSON(ctx, key, value, expiration)
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 )
 
 type RedisClient struct {
@@ -53,7 +54,7 @@ func (r *RedisClient) SetCache(ctx context.Context, key string, value interface{
 func (r *RedisClient) GetCache(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return "", fmt.Errorf("cache key %s does not exist", key)
+		return "", fmt.Errorf("cache key %s does not exist: %w", key, domain.ErrCacheMiss)
 	} else if err != nil {
 		return "", fmt.Errorf("failed to get cache for key %s: %w", key, err)
 	}
@@ -76,6 +77,15 @@ func (r *RedisClient) ExistsCache(ctx context.Context, key string) (bool, error)
 	return exists > 0, nil
 }
 
+// IncrementCache atomically increments the integer stored at key, starting from 0, and returns the new value
+func (r *RedisClient) IncrementCache(ctx context.Context, key string) (int64, error) {
+	value, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment cache key %s: %w", key, err)
+	}
+	return value, nil
+}
+
 func (r *RedisClient) SetCacheJSON(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	data, err := json.Marshal(value)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-redis/redismock/v9"
 	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 )
 
 func TestSetCache(t *testing.T) {
@@ -82,6 +83,7 @@ func TestGetCacheNotExists(t *testing.T) {
 	value, err := redisClient.GetCache(ctx, key)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "cache key "+key+" does not exist")
+	assert.ErrorIs(t, err, domain.ErrCacheMiss)
 	assert.Equal(t, "", value)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -180,6 +182,41 @@ func TestExistsCacheError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestIncrementCache(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	redisClient := &RedisClient{client: db}
+
+	ctx := context.Background()
+	key := "counterKey"
+
+	mock.ExpectIncr(key).SetVal(3)
+
+	value, err := redisClient.IncrementCache(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIncrementCacheError(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	redisClient := &RedisClient{client: db}
+
+	ctx := context.Background()
+	key := "counterKey"
+
+	mock.ExpectIncr(key).SetErr(errors.New("incr error"))
+
+	value, err := redisClient.IncrementCache(ctx, key)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to increment cache key")
+	assert.Zero(t, value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestSetCacheJSON(t *testing.T) {
 	db, mock := redismock.NewClientMock()
 	defer db.Close()
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"gorm.io/gorm"
+)
+
+// rebuildCarrierAggregatesSQL recomputes the running totals from quote_offers, the same way
+// migration 0008 seeds them
+const rebuildCarrierAggregatesSQL = `INSERT INTO carrier_aggregates (carrier, quote_count, offer_count, total_price, total_price_squares,
+	min_price, max_price, total_deadline_days, min_deadline_days, max_deadline_days, cheapest_count, updated_at)
+SELECT carrier,
+	COUNT(DISTINCT quote_id),
+	COUNT(*),
+	SUM(price),
+	SUM(price * price),
+	MIN(price),
+	MAX(price),
+	SUM(deadline_days),
+	MIN(deadline_days),
+	MAX(deadline_days),
+	COUNT(DISTINCT quote_id) FILTER (WHERE price = quote_min_price),
+	now()
+FROM (
+	SELECT *, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price FROM quote_offers
+) AS offers
+GROUP BY carrier`
+
+// RebuildCarrierAggregates replaces the running per-carrier aggregates with totals recomputed
+// from quote_offers, reconciling them after offers were changed outside SaveQuote.
+// The table lock blocks concurrent SaveQuote upserts but not reads: a save whose offers are not
+// committed yet adds its totals after the rebuild commits, so no quote is lost or counted twice.
+func RebuildCarrierAggregates(ctx context.Context, db *gorm.DB) error {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE carrier_aggregates IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM carrier_aggregates").Error; err != nil {
+			return err
+		}
+		return tx.Exec(rebuildCarrierAggregatesSQL).Error
+	})
+	if err != nil {
+		return domain.NewPersistenceError(err)
+	}
+	return nil
+}
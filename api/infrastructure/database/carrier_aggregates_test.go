@@ -0,0 +1,51 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/database"
+)
+
+func TestRebuildCarrierAggregates(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	// The totals are replaced in one transaction, with saves blocked until it commits
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE carrier_aggregates IN SHARE ROW EXCLUSIVE MODE`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM carrier_aggregates`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO carrier_aggregates (carrier, quote_count, offer_count,`) +
+		`.*` + regexp.QuoteMeta(`FROM quote_offers ) AS offers GROUP BY carrier`)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err := database.RebuildCarrierAggregates(context.Background(), db)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRebuildCarrierAggregatesRollsBack(t *testing.T) {
+	db, mock := setupMockDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE carrier_aggregates`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM carrier_aggregates`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO carrier_aggregates`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err := database.RebuildCarrierAggregates(context.Background(), db)
+
+	assert.True(t, errors.Is(err, domain.ErrPersistenceFailure))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
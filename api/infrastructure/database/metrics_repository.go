@@ -3,9 +3,8 @@ package database
 import (
 	"context"
 	"fmt"
-	"math"
-	"sort"
-	"sync"
+	"strings"
+	"time"
 
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 	"gorm.io/gorm"
@@ -21,171 +20,243 @@ func NewMetricsRepository(db *gorm.DB) domain.MetricsRepository {
 	}
 }
 
-func (r *MetricsRepositoryImpl) GetMetrics(ctx context.Context, lastQuotes int) (*domain.MetricsResponse, error) {
-	var quotes []domain.QuoteResponse
-
-	// Add deterministic ordering with secondary sort on ID to ensure consistent results
-	// Also enable debugging to track the queries being executed
-	query := r.db.Debug().Order("created_at DESC, id DESC")
-
-	if lastQuotes > 0 {
-		query = query.Limit(lastQuotes)
+// offerStatsSelect aggregates the offers of each group. Ties for the cheapest offer
+// of a quote count for every tied group.
+const offerStatsSelect = "COUNT(*) AS total_quotes, " +
+	"SUM(price) AS total_shipping_price, AVG(price) AS average_shipping_price, " +
+	"MIN(price) AS min_shipping_price, MAX(price) AS max_shipping_price, " +
+	"COALESCE(stddev_pop(price), 0) AS stddev_shipping_price, " +
+	"AVG(deadline_days) AS average_delivery_days, " +
+	"MIN(deadline_days) AS min_delivery_days, MAX(deadline_days) AS max_delivery_days, " +
+	"COUNT(DISTINCT quote_id) FILTER (WHERE price = quote_min_price) AS cheapest_count, " +
+	"COUNT(DISTINCT quote_id) FILTER (WHERE price = quote_min_price)::float / COUNT(DISTINCT quote_id) AS cheapest_share"
+
+// offerPercentilesSelect sorts the prices of each group, the costliest part of the aggregation
+const offerPercentilesSelect = "percentile_cont(0.5) WITHIN GROUP (ORDER BY price) AS median_shipping_price, " +
+	"percentile_cont(0.9) WITHIN GROUP (ORDER BY price) AS p90_shipping_price, " +
+	"percentile_cont(0.95) WITHIN GROUP (ORDER BY price) AS p95_shipping_price"
+
+// offerStatsColumns returns the aggregate columns selected for the query
+func offerStatsColumns(metricsQuery domain.MetricsQuery) string {
+	if metricsQuery.SkipPercentiles {
+		return offerStatsSelect
 	}
+	return offerStatsSelect + ", " + offerPercentilesSelect
+}
 
-	// Execute the query to find quotes
-	if err := query.Find(&quotes).Error; err != nil {
-		return nil, err
+// metricsKeyColumns returns the selected key columns of a metrics row and the
+// columns to group and order by
+func metricsKeyColumns(key domain.MetricsKey) (selectSQL, groupSQL string) {
+	switch key {
+	case domain.KeyCarrierService:
+		return "carrier AS carrier_name, COALESCE(service, '') AS service", "carrier, COALESCE(service, '')"
+	case domain.KeyModal:
+		return "modal", "modal"
+	default:
+		return "carrier AS carrier_name", "carrier"
 	}
+}
 
-	// If no quotes found, return empty metrics
-	if len(quotes) == 0 {
-		return &domain.MetricsResponse{
-			CarrierMetrics: []domain.QuoteMetrics{},
-			CheapestAndMostExpensive: domain.CheapestAndMostExpensive{
-				CheapestShipping:      0,
-				MostExpensiveShipping: 0,
-			},
-		}, nil
+// stateRegionSQL maps the recipient CEP to its UF using the bundled CEP ranges
+var stateRegionSQL = func() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, r := range domain.StateZipcodeRanges {
+		fmt.Fprintf(&b, " WHEN left(recipient_zipcode, 5) BETWEEN '%s' AND '%s' THEN '%s'", r.First, r.Last, r.State)
 	}
+	b.WriteString(" END")
+	return b.String()
+}()
+
+// regionCarrierMetrics is one row of the per-region, per-key GROUP BY
+type regionCarrierMetrics struct {
+	Region string
+	domain.QuoteMetrics
+}
 
-	// Debug: print each quote's carriers
-	for i, quote := range quotes {
-		fmt.Printf("Quote %d has %d carriers\n", i, len(quote.Carriers))
+func (r *MetricsRepositoryImpl) GetMetrics(ctx context.Context, metricsQuery domain.MetricsQuery) (*domain.MetricsResponse, error) {
+	if metricsQuery.Aggregated() {
+		return r.getAggregatedMetrics(ctx)
 	}
 
-	// Initialize response structure
 	response := &domain.MetricsResponse{
 		CarrierMetrics: []domain.QuoteMetrics{},
-		CheapestAndMostExpensive: domain.CheapestAndMostExpensive{
-			CheapestShipping:      math.MaxFloat64,
-			MostExpensiveShipping: 0,
-		},
-	}
-
-	// Track metrics per carrier with a mutex for thread safety
-	carrierMetrics := make(map[string]*domain.QuoteMetrics)
-	var metricsMutex sync.Mutex
-	var cheapestMutex sync.Mutex
-
-	// Use a wait group to wait for all goroutines to finish
-	var wg sync.WaitGroup
-
-	// Process quotes concurrently
-	for i, quote := range quotes {
-		// Skip quotes with no carriers
-		if len(quote.Carriers) == 0 {
-			fmt.Printf("Warning: Quote ID %d has no carriers\n", quote.ID)
-			continue
+	}
+	keySelect, keyGroup := metricsKeyColumns(metricsQuery.Key)
+	statsSelect := offerStatsColumns(metricsQuery)
+
+	err := r.db.WithContext(ctx).
+		Table("(?) AS offers", r.windowOffers(metricsQuery)).
+		Select(keySelect + ", " + statsSelect).
+		Group(keyGroup).
+		Order(keyGroup).
+		Scan(&response.CarrierMetrics).Error
+	if err != nil {
+		return nil, domain.NewPersistenceError(err)
+	}
+	response.CheapestAndMostExpensive = cheapestAndMostExpensive(response.CarrierMetrics)
+
+	if metricsQuery.Region == "" {
+		return response, nil
+	}
+
+	// Percentiles can't be combined across regions, so the breakdown is a separate aggregation
+	var rows []regionCarrierMetrics
+	err = r.db.WithContext(ctx).
+		Table("(?) AS offers", r.windowOffers(metricsQuery)).
+		Select("region, " + keySelect + ", " + statsSelect).
+		Group("region, " + keyGroup).
+		Order("region, " + keyGroup).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, domain.NewPersistenceError(err)
+	}
+
+	// Rows are ordered by region, so each region's rows are contiguous
+	response.Regions = []domain.RegionMetrics{}
+	for _, row := range rows {
+		if n := len(response.Regions); n == 0 || response.Regions[n-1].Region != row.Region {
+			response.Regions = append(response.Regions, domain.RegionMetrics{
+				Region:         row.Region,
+				CarrierMetrics: []domain.QuoteMetrics{},
+			})
 		}
+		region := &response.Regions[len(response.Regions)-1]
+		region.CarrierMetrics = append(region.CarrierMetrics, row.QuoteMetrics)
+	}
+	for i := range response.Regions {
+		response.Regions[i].CheapestAndMostExpensive = cheapestAndMostExpensive(response.Regions[i].CarrierMetrics)
+	}
+
+	return response, nil
+}
+
+// getAggregatedMetrics serves the metrics of all quotes from the running per-carrier
+// aggregates maintained by SaveQuote, so the cost doesn't grow with the number of offers
+func (r *MetricsRepositoryImpl) getAggregatedMetrics(ctx context.Context) (*domain.MetricsResponse, error) {
+	var aggregates []domain.CarrierAggregate
+	if err := r.db.WithContext(ctx).Order("carrier").Find(&aggregates).Error; err != nil {
+		return nil, domain.NewPersistenceError(err)
+	}
 
-		// Add to wait group
-		wg.Add(1)
-
-		// Process each quote in a separate goroutine
-		go func(index int, q domain.QuoteResponse) {
-			defer wg.Done()
-
-			// Check for context cancellation
-			select {
-			case <-ctx.Done():
-				// Context was cancelled, stop processing
-				return
-			default:
-				// Continue processing
-			}
-
-			// Process all carriers in this quote
-			for j, carrier := range q.Carriers {
-				// Check for context cancellation periodically
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					// Continue processing
-				}
-
-				fmt.Printf("Processing quote %d, carrier %d: %s with price %.2f\n",
-					index, j, carrier.Name, carrier.Price)
-
-				// Update cheapest/most expensive with mutex protection
-				cheapestMutex.Lock()
-				if carrier.Price < response.CheapestAndMostExpensive.CheapestShipping {
-					response.CheapestAndMostExpensive.CheapestShipping = carrier.Price
-				}
-				if carrier.Price > response.CheapestAndMostExpensive.MostExpensiveShipping {
-					response.CheapestAndMostExpensive.MostExpensiveShipping = carrier.Price
-				}
-				cheapestMutex.Unlock()
-
-				// Update carrier metrics with mutex protection
-				metricsMutex.Lock()
-				if _, exists := carrierMetrics[carrier.Name]; !exists {
-					carrierMetrics[carrier.Name] = &domain.QuoteMetrics{
-						CarrierName:          carrier.Name,
-						TotalQuotes:          0,
-						TotalShippingPrice:   0,
-						AverageShippingPrice: 0,
-					}
-				}
-				carrierMetrics[carrier.Name].TotalQuotes++
-				carrierMetrics[carrier.Name].TotalShippingPrice += carrier.Price
-				metricsMutex.Unlock()
-			}
-		}(i, quote)
-	}
-
-	// Wait for all goroutines to complete
-	wg.Wait()
-
-	// Convert map to slice in a deterministic order (alphabetical by carrier name)
-	var carrierNames []string
-	for name := range carrierMetrics {
-		carrierNames = append(carrierNames, name)
-	}
-	sort.Strings(carrierNames)
-
-	// Use channels to collect metrics in parallel
-	metricsChan := make(chan domain.QuoteMetrics, len(carrierNames))
-	var avgWg sync.WaitGroup
-
-	// Calculate average prices in parallel
-	for _, name := range carrierNames {
-		avgWg.Add(1)
-		go func(name string, metrics *domain.QuoteMetrics) {
-			defer avgWg.Done()
-
-			// Clone the metrics to avoid concurrent modification
-			metricsCopy := *metrics
-
-			if metricsCopy.TotalQuotes > 0 {
-				metricsCopy.AverageShippingPrice = metricsCopy.TotalShippingPrice / float64(metricsCopy.TotalQuotes)
-			}
-
-			// Send to channel
-			metricsChan <- metricsCopy
-		}(name, carrierMetrics[name])
-	}
-
-	// Close channel when all goroutines are done
-	go func() {
-		avgWg.Wait()
-		close(metricsChan)
-	}()
-
-	// Collect results from channel
-	for metric := range metricsChan {
-		response.CarrierMetrics = append(response.CarrierMetrics, metric)
-	}
-
-	// Sort the final metrics slice to maintain deterministic order
-	sort.Slice(response.CarrierMetrics, func(i, j int) bool {
-		return response.CarrierMetrics[i].CarrierName < response.CarrierMetrics[j].CarrierName
-	})
-
-	// Handle edge case when no valid carriers were found
-	if response.CheapestAndMostExpensive.CheapestShipping == math.MaxFloat64 {
-		response.CheapestAndMostExpensive.CheapestShipping = 0
+	response := &domain.MetricsResponse{
+		CarrierMetrics: make([]domain.QuoteMetrics, 0, len(aggregates)),
+	}
+	for _, aggregate := range aggregates {
+		response.CarrierMetrics = append(response.CarrierMetrics, aggregate.Metrics())
 	}
+	response.CheapestAndMostExpensive = cheapestAndMostExpensive(response.CarrierMetrics)
 
 	return response, nil
 }
+
+// windowOffers selects the offers of the quotes in the query window, each tagged with
+// the cheapest price of its quote and, when grouping by region, with its region
+func (r *MetricsRepositoryImpl) windowOffers(metricsQuery domain.MetricsQuery) *gorm.DB {
+	offers := r.db.Model(&domain.QuoteOffer{})
+
+	switch metricsQuery.Region {
+	case domain.RegionState:
+		offers = offers.Select("*, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price, "+
+			"COALESCE("+stateRegionSQL+", ?) AS region", domain.RegionUnknown)
+	case domain.RegionCepPrefix:
+		offers = offers.Select("*, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price, "+
+			"COALESCE(NULLIF(left(recipient_zipcode, ?), ''), ?) AS region", metricsQuery.CepPrefixLength, domain.RegionUnknown)
+	default:
+		offers = offers.Select("*, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price")
+	}
+
+	if metricsQuery.LastQuotes > 0 {
+		// The window selects the quotes, and only the latest N of them are aggregated
+		latest := r.db.Model(&domain.QuoteResponse{}).
+			Select("id").
+			Order("created_at DESC, id DESC").
+			Limit(metricsQuery.LastQuotes)
+		latest = applyTimeWindow(latest, metricsQuery)
+		return offers.Where("quote_id IN (?)", latest)
+	}
+
+	// Offers carry the quote's created_at, so the window needs no join
+	return applyTimeWindow(offers, metricsQuery)
+}
+
+func cheapestAndMostExpensive(carrierMetrics []domain.QuoteMetrics) domain.CheapestAndMostExpensive {
+	var result domain.CheapestAndMostExpensive
+	for i, metrics := range carrierMetrics {
+		if i == 0 || metrics.MinShippingPrice < result.CheapestShipping {
+			result.CheapestShipping = metrics.MinShippingPrice
+		}
+		if metrics.MaxShippingPrice > result.MostExpensiveShipping {
+			result.MostExpensiveShipping = metrics.MaxShippingPrice
+		}
+	}
+	return result
+}
+
+func applyTimeWindow(query *gorm.DB, metricsQuery domain.MetricsQuery) *gorm.DB {
+	if metricsQuery.From != nil {
+		query = query.Where("created_at >= ?", *metricsQuery.From)
+	}
+	if metricsQuery.To != nil {
+		query = query.Where("created_at < ?", *metricsQuery.To)
+	}
+	return query
+}
+
+// seriesAggregate is one row of the per-bucket, per-carrier GROUP BY over quote_offers
+type seriesAggregate struct {
+	BucketStart          time.Time
+	CarrierName          string
+	TotalQuotes          int
+	AverageShippingPrice float64
+	MinShippingPrice     float64
+	MaxShippingPrice     float64
+}
+
+func (r *MetricsRepositoryImpl) GetMetricsSeries(ctx context.Context, seriesQuery domain.MetricsSeriesQuery) (*domain.MetricsSeries, error) {
+	var rows []seriesAggregate
+
+	location := seriesQuery.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	// Buckets are truncated in the query's time zone so days and weeks start at local midnight
+	query := r.db.WithContext(ctx).
+		Model(&domain.QuoteOffer{}).
+		Select("date_trunc(?, created_at, ?) AS bucket_start, carrier AS carrier_name, COUNT(*) AS total_quotes, "+
+			"AVG(price) AS average_shipping_price, MIN(price) AS min_shipping_price, MAX(price) AS max_shipping_price",
+			string(seriesQuery.Bucket), location.String()).
+		Group("carrier_name, bucket_start").
+		Order("carrier_name, bucket_start")
+	query = applyTimeWindow(query, seriesQuery.MetricsQuery)
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, domain.NewPersistenceError(err)
+	}
+
+	series := &domain.MetricsSeries{
+		Bucket:   seriesQuery.Bucket,
+		Carriers: []domain.CarrierSeries{},
+	}
+
+	// Rows are ordered by carrier, so each carrier's points are contiguous
+	for _, row := range rows {
+		if n := len(series.Carriers); n == 0 || series.Carriers[n-1].CarrierName != row.CarrierName {
+			series.Carriers = append(series.Carriers, domain.CarrierSeries{
+				CarrierName: row.CarrierName,
+				Points:      []domain.MetricsPoint{},
+			})
+		}
+		carrier := &series.Carriers[len(series.Carriers)-1]
+		carrier.Points = append(carrier.Points, domain.MetricsPoint{
+			BucketStart:          row.BucketStart.In(location),
+			TotalQuotes:          row.TotalQuotes,
+			AverageShippingPrice: row.AverageShippingPrice,
+			MinShippingPrice:     row.MinShippingPrice,
+			MaxShippingPrice:     row.MaxShippingPrice,
+		})
+	}
+
+	return series, nil
+}
@@ -2,99 +2,347 @@ package database_test
 
 import (
 	"context"
+	"errors"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
-	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/database"
-	"gorm.io/gorm"
 )
 
+var carrierMetricsColumns = []string{
+	"carrier_name", "total_quotes", "total_shipping_price", "average_shipping_price",
+	"min_shipping_price", "max_shipping_price", "median_shipping_price", "p90_shipping_price", "p95_shipping_price",
+	"stddev_shipping_price", "average_delivery_days", "min_delivery_days", "max_delivery_days",
+	"cheapest_count", "cheapest_share",
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
 func TestMetricsRepository_GetMetrics(t *testing.T) {
-	// This test uses a mock QuoteRepository directly rather than a DB mock
-	// because the MetricsRepository uses the QuoteRepository
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
 
-	// Create mock repository
-	mockQuoteRepo := new(mocks.MockQuoteRepository)
+	// Aggregation happens in SQL, restricted to the offers of the latest quotes
+	rows := sqlmock.NewRows(carrierMetricsColumns).
+		AddRow("Correios", 1, 25.0, 25.0, 25.0, 25.0, 25.0, 25.0, 25.0, 0.0, 1.0, 1, 1, 0, 0.0).
+		AddRow("EXPRESSO FR", 2, 35.0, 17.5, 15.0, 20.0, 17.5, 19.5, 19.75, 2.5, 3.5, 3, 4, 2, 1.0)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT carrier AS carrier_name, COUNT(*) AS total_quotes`) +
+		`.*` + regexp.QuoteMeta(`percentile_cont(0.5) WITHIN GROUP (ORDER BY price) AS median_shipping_price`) +
+		`.*` + regexp.QuoteMeta(`FROM (SELECT *, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price FROM "quote_offers" `+
+		`WHERE quote_id IN (SELECT "id" FROM "quote_responses" WHERE "quote_responses"."deleted_at" IS NULL ORDER BY created_at DESC, id DESC LIMIT 10)) AS offers `+
+		`GROUP BY "carrier" ORDER BY carrier`)).
+		WillReturnRows(rows)
 
-	// Create metrics repository with a nil DB (it won't be used directly)
-	metricsRepo := &database.MetricsRepositoryImpl{
-		// We're using reflection to set the db field, so this test might break if the implementation changes
-		// In a real project, you might want to refactor to use dependency injection for the QuoteRepository
-	}
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{LastQuotes: 10})
 
-	// Setup test data
-	now := time.Now()
-	quotes := []domain.QuoteResponse{
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.QuoteMetrics{
 		{
-			Model: gorm.Model{
-				ID:        1,
-				CreatedAt: now,
-				UpdatedAt: now,
-			},
-			Carriers: []domain.Carrier{
-				{
-					Name:     "EXPRESSO FR",
-					Service:  "Rodoviário",
-					Deadline: "3",
-					Price:    15.0,
-				},
-			},
+			CarrierName: "Correios", TotalQuotes: 1, TotalShippingPrice: 25.0, AverageShippingPrice: 25.0,
+			MinShippingPrice: 25.0, MaxShippingPrice: 25.0, MedianShippingPrice: floatPtr(25.0), P90ShippingPrice: floatPtr(25.0), P95ShippingPrice: floatPtr(25.0),
+			AverageDeliveryDays: 1.0, MinDeliveryDays: 1, MaxDeliveryDays: 1,
 		},
 		{
-			Model: gorm.Model{
-				ID:        2,
-				CreatedAt: now.Add(-1 * time.Hour),
-				UpdatedAt: now.Add(-1 * time.Hour),
-			},
-			Carriers: []domain.Carrier{
-				{
-					Name:     "EXPRESSO FR",
-					Service:  "Rodoviário",
-					Deadline: "3",
-					Price:    20.0,
-				},
-			},
+			CarrierName: "EXPRESSO FR", TotalQuotes: 2, TotalShippingPrice: 35.0, AverageShippingPrice: 17.5,
+			MinShippingPrice: 15.0, MaxShippingPrice: 20.0, MedianShippingPrice: floatPtr(17.5), P90ShippingPrice: floatPtr(19.5), P95ShippingPrice: floatPtr(19.75),
+			StddevShippingPrice: 2.5, AverageDeliveryDays: 3.5, MinDeliveryDays: 3, MaxDeliveryDays: 4,
+			CheapestCount: 2, CheapestShare: 1.0,
+		},
+	}, result.CarrierMetrics)
+	assert.Equal(t, 15.0, result.CheapestAndMostExpensive.CheapestShipping)
+	assert.Equal(t, 25.0, result.CheapestAndMostExpensive.MostExpensiveShipping)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsAllQuotes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	// Without a window or percentiles the running aggregates are read instead of the offers
+	columns := []string{
+		"carrier", "quote_count", "offer_count", "total_price", "total_price_squares", "min_price", "max_price",
+		"total_deadline_days", "min_deadline_days", "max_deadline_days", "cheapest_count", "updated_at",
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "carrier_aggregates" ORDER BY carrier`)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("Correios", 1, 1, 25.0, 625.0, 25.0, 25.0, 1, 1, 1, 0, time.Now()).
+			AddRow("EXPRESSO FR", 2, 2, 35.0, 625.0, 15.0, 20.0, 7, 3, 4, 2, time.Now()))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{SkipPercentiles: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.QuoteMetrics{
+		{
+			CarrierName: "Correios", TotalQuotes: 1, TotalShippingPrice: 25.0, AverageShippingPrice: 25.0,
+			MinShippingPrice: 25.0, MaxShippingPrice: 25.0, AverageDeliveryDays: 1.0, MinDeliveryDays: 1, MaxDeliveryDays: 1,
 		},
 		{
-			Model: gorm.Model{
-				ID:        3,
-				CreatedAt: now.Add(-2 * time.Hour),
-				UpdatedAt: now.Add(-2 * time.Hour),
-			},
-			Carriers: []domain.Carrier{
-				{
-					Name:     "Correios",
-					Service:  "SEDEX",
-					Deadline: "1",
-					Price:    25.0,
-				},
-			},
+			CarrierName: "EXPRESSO FR", TotalQuotes: 2, TotalShippingPrice: 35.0, AverageShippingPrice: 17.5,
+			MinShippingPrice: 15.0, MaxShippingPrice: 20.0, StddevShippingPrice: 2.5,
+			AverageDeliveryDays: 3.5, MinDeliveryDays: 3, MaxDeliveryDays: 4, CheapestCount: 2, CheapestShare: 1.0,
 		},
-	}
+	}, result.CarrierMetrics)
+	assert.Equal(t, domain.CheapestAndMostExpensive{CheapestShipping: 15.0, MostExpensiveShipping: 25.0}, result.CheapestAndMostExpensive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	// Setup mock expectations
-	lastQuotes := 10
-	mockQuoteRepo.On("GetLastQuotes", mock.Anything, lastQuotes).Return(quotes, nil)
+func TestMetricsRepository_GetMetricsAllQuotesEmpty(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
 
-	// Skip this test as it requires accessing private fields or refactoring
-	t.Skip("Skipping test that requires accessing private fields or refactoring")
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "carrier_aggregates"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"carrier"}))
 
-	// Call the repo method
-	result, err := metricsRepo.GetMetrics(context.Background(), lastQuotes)
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{SkipPercentiles: true})
 
-	// Assertions
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Len(t, result.CarrierMetrics, 2) // Two unique carriers
+	assert.NotNil(t, result.CarrierMetrics)
+	assert.Empty(t, result.CarrierMetrics)
+	assert.Equal(t, domain.CheapestAndMostExpensive{}, result.CheapestAndMostExpensive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	// Check cheapest and most expensive
-	assert.Equal(t, 15.0, result.CheapestAndMostExpensive.CheapestShipping)
-	assert.Equal(t, 25.0, result.CheapestAndMostExpensive.MostExpensiveShipping)
+func TestMetricsRepository_GetMetricsAllQuotesWithPercentiles(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
 
-	// Verify all expectations were met
-	mockQuoteRepo.AssertExpectations(t)
+	// By default the percentiles are computed, so every offer is read even without a window
+	rows := sqlmock.NewRows(carrierMetricsColumns).
+		AddRow("EXPRESSO FR", 2, 35.0, 17.5, 15.0, 20.0, 17.5, 19.5, 19.75, 2.5, 3.5, 3, 4, 2, 1.0)
+	mock.ExpectQuery(regexp.QuoteMeta(`percentile_cont(0.95) WITHIN GROUP (ORDER BY price) AS p95_shipping_price ` +
+		`FROM (SELECT *, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price FROM "quote_offers") AS offers ` +
+		`GROUP BY "carrier" ORDER BY carrier`)).
+		WillReturnRows(rows)
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.CarrierMetrics, 1)
+	assert.Equal(t, floatPtr(17.5), result.CarrierMetrics[0].MedianShippingPrice)
+	assert.Equal(t, floatPtr(19.75), result.CarrierMetrics[0].P95ShippingPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsSkipPercentiles(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	// A window still reads the offers, without sorting their prices
+	mock.ExpectQuery(regexp.QuoteMeta(`AS cheapest_share FROM (SELECT *, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price ` +
+		`FROM "quote_offers" WHERE quote_id IN`)).
+		WillReturnRows(sqlmock.NewRows(carrierMetricsColumns))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{LastQuotes: 10, SkipPercentiles: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.CarrierMetrics)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "quote_offers"`)).
+		WillReturnError(errors.New("connection reset"))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{LastQuotes: 10})
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, domain.ErrPersistenceFailure))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsTimeWindow(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	from := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 3, 0, 0, 0, time.UTC)
+
+	// Without last_quotes the window filters the offers directly
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "quote_offers" WHERE created_at >= $1 AND created_at < $2) AS offers GROUP BY "carrier"`)).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows(carrierMetricsColumns).AddRow("Correios", 1, 25.0, 25.0, 25.0, 25.0, 25.0, 25.0, 25.0, 0.0, 1.0, 1, 1, 1, 1.0))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{From: &from, To: &to})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.CarrierMetrics, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsTimeWindowLastQuotes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	from := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	// With last_quotes the window selects the latest quotes
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE quote_id IN (SELECT "id" FROM "quote_responses" WHERE created_at >= $1 AND "quote_responses"."deleted_at" IS NULL ORDER BY created_at DESC, id DESC LIMIT 5)`)).
+		WithArgs(from).
+		WillReturnRows(sqlmock.NewRows(carrierMetricsColumns))
+
+	_, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{From: &from, LastQuotes: 5})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsSeries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	saoPaulo := time.FixedZone("America/Sao_Paulo", -3*60*60)
+	from := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 3, 3, 0, 0, 0, time.UTC)
+	day1 := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	columns := []string{"bucket_start", "carrier_name", "total_quotes", "average_shipping_price", "min_shipping_price", "max_shipping_price"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(day1, "Correios", 1, 25.0, 25.0, 25.0).
+		AddRow(day1, "EXPRESSO FR", 2, 17.5, 15.0, 20.0).
+		AddRow(day2, "EXPRESSO FR", 1, 18.0, 18.0, 18.0)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT date_trunc($1, created_at, $2) AS bucket_start, carrier AS carrier_name`)+
+		`.*`+regexp.QuoteMeta(`FROM "quote_offers" WHERE created_at >= $3 AND created_at < $4 GROUP BY carrier_name, bucket_start ORDER BY carrier_name, bucket_start`)).
+		WithArgs("day", "America/Sao_Paulo", from, to).
+		WillReturnRows(rows)
+
+	series, err := repo.GetMetricsSeries(context.Background(), domain.MetricsSeriesQuery{
+		MetricsQuery: domain.MetricsQuery{From: &from, To: &to, Location: saoPaulo},
+		Bucket:       domain.BucketDay,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BucketDay, series.Bucket)
+	assert.Len(t, series.Carriers, 2)
+	assert.Equal(t, "Correios", series.Carriers[0].CarrierName)
+	assert.Len(t, series.Carriers[0].Points, 1)
+	assert.Equal(t, "EXPRESSO FR", series.Carriers[1].CarrierName)
+	assert.Equal(t, []domain.MetricsPoint{
+		{BucketStart: day1.In(saoPaulo), TotalQuotes: 2, AverageShippingPrice: 17.5, MinShippingPrice: 15.0, MaxShippingPrice: 20.0},
+		{BucketStart: day2.In(saoPaulo), TotalQuotes: 1, AverageShippingPrice: 18.0, MinShippingPrice: 18.0, MaxShippingPrice: 18.0},
+	}, series.Carriers[1].Points)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsSeriesError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "quote_offers"`)).
+		WillReturnError(errors.New("connection reset"))
+
+	series, err := repo.GetMetricsSeries(context.Background(), domain.MetricsSeriesQuery{Bucket: domain.BucketHour})
+
+	assert.Nil(t, series)
+	assert.True(t, errors.Is(err, domain.ErrPersistenceFailure))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsByState(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "quote_offers") AS offers GROUP BY "carrier" ORDER BY carrier`)).
+		WillReturnRows(sqlmock.NewRows(carrierMetricsColumns).
+			AddRow("Correios", 2, 45.0, 22.5, 20.0, 25.0, 22.5, 24.5, 24.75, 2.5, 1.0, 1, 1, 1, 0.5).
+			AddRow("EXPRESSO FR", 2, 35.0, 17.5, 15.0, 20.0, 17.5, 19.5, 19.75, 2.5, 3.5, 3, 4, 1, 0.5))
+
+	// The breakdown maps each offer's CEP to its UF with the bundled ranges
+	regionColumns := append([]string{"region"}, carrierMetricsColumns...)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT region, carrier AS carrier_name`) +
+		`.*` + regexp.QuoteMeta(`COALESCE(CASE WHEN left(recipient_zipcode, 5) BETWEEN '01000' AND '19999' THEN 'SP'`) +
+		`.*` + regexp.QuoteMeta(`END, $1) AS region FROM "quote_offers") AS offers GROUP BY region, carrier ORDER BY region, carrier`)).
+		WithArgs(domain.RegionUnknown).
+		WillReturnRows(sqlmock.NewRows(regionColumns).
+			AddRow("BA", "Correios", 1, 20.0, 20.0, 20.0, 20.0, 20.0, 20.0, 20.0, 0.0, 1.0, 1, 1, 1, 1.0).
+			AddRow("BA", "EXPRESSO FR", 1, 20.0, 20.0, 20.0, 20.0, 20.0, 20.0, 20.0, 0.0, 4.0, 4, 4, 1, 1.0).
+			AddRow("SP", "Correios", 1, 25.0, 25.0, 25.0, 25.0, 25.0, 25.0, 25.0, 0.0, 1.0, 1, 1, 0, 0.0).
+			AddRow("SP", "EXPRESSO FR", 1, 15.0, 15.0, 15.0, 15.0, 15.0, 15.0, 15.0, 0.0, 3.0, 3, 3, 1, 1.0))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{Region: domain.RegionState})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.CarrierMetrics, 2)
+	assert.Len(t, result.Regions, 2)
+	assert.Equal(t, "BA", result.Regions[0].Region)
+	assert.Len(t, result.Regions[0].CarrierMetrics, 2)
+	assert.Equal(t, domain.CheapestAndMostExpensive{CheapestShipping: 20.0, MostExpensiveShipping: 20.0}, result.Regions[0].CheapestAndMostExpensive)
+	assert.Equal(t, "SP", result.Regions[1].Region)
+	assert.Equal(t, "EXPRESSO FR", result.Regions[1].CarrierMetrics[1].CarrierName)
+	assert.Equal(t, 1, result.Regions[1].CarrierMetrics[1].CheapestCount)
+	assert.Equal(t, domain.CheapestAndMostExpensive{CheapestShipping: 15.0, MostExpensiveShipping: 25.0}, result.Regions[1].CheapestAndMostExpensive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsByCepPrefix(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "quote_offers") AS offers GROUP BY "carrier"`)).
+		WillReturnRows(sqlmock.NewRows(carrierMetricsColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(`COALESCE(NULLIF(left(recipient_zipcode, $1), ''), $2) AS region FROM "quote_offers") AS offers GROUP BY region, carrier`)).
+		WithArgs(2, domain.RegionUnknown).
+		WillReturnRows(sqlmock.NewRows(append([]string{"region"}, carrierMetricsColumns...)))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{Region: domain.RegionCepPrefix, CepPrefixLength: 2})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Regions)
+	assert.Empty(t, result.Regions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsByCarrierService(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	columns := append([]string{"service"}, carrierMetricsColumns...)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT carrier AS carrier_name, COALESCE(service, '') AS service, COUNT(*) AS total_quotes`) +
+		`.*` + regexp.QuoteMeta(`AS offers GROUP BY carrier, COALESCE(service, '') ORDER BY carrier, COALESCE(service, '')`)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("Expresso", "EXPRESSO FR", 1, 40.0, 40.0, 40.0, 40.0, 40.0, 40.0, 40.0, 0.0, 1.0, 1, 1, 0, 0.0).
+			AddRow("Rodoviário", "EXPRESSO FR", 2, 30.0, 15.0, 14.0, 16.0, 15.0, 15.8, 15.9, 1.0, 4.0, 4, 4, 2, 1.0))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{Key: domain.KeyCarrierService})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.CarrierMetrics, 2)
+	assert.Equal(t, "EXPRESSO FR", result.CarrierMetrics[0].CarrierName)
+	assert.Equal(t, "Expresso", result.CarrierMetrics[0].Service)
+	assert.Equal(t, "Rodoviário", result.CarrierMetrics[1].Service)
+	assert.Equal(t, 15.0, result.CarrierMetrics[1].AverageShippingPrice)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetricsRepository_GetMetricsByStateAndModal(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewMetricsRepository(db)
+
+	columns := append([]string{"modal"}, carrierMetricsColumns[1:]...)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT modal, COUNT(*) AS total_quotes`) +
+		`.*` + regexp.QuoteMeta(`AS offers GROUP BY "modal" ORDER BY modal`)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("Aéreo", 1, 40.0, 40.0, 40.0, 40.0, 40.0, 40.0, 40.0, 0.0, 1.0, 1, 1, 0, 0.0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT region, modal, COUNT(*) AS total_quotes`) +
+		`.*` + regexp.QuoteMeta(`GROUP BY region, modal ORDER BY region, modal`)).
+		WillReturnRows(sqlmock.NewRows(append([]string{"region"}, columns...)).
+			AddRow("SP", "Aéreo", 1, 40.0, 40.0, 40.0, 40.0, 40.0, 40.0, 40.0, 0.0, 1.0, 1, 1, 0, 0.0))
+
+	result, err := repo.GetMetrics(context.Background(), domain.MetricsQuery{Region: domain.RegionState, Key: domain.KeyModal})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Aéreo", result.CarrierMetrics[0].Modal)
+	assert.Empty(t, result.CarrierMetrics[0].CarrierName)
+	assert.Equal(t, "SP", result.Regions[0].Region)
+	assert.Equal(t, "Aéreo", result.Regions[0].CarrierMetrics[0].Modal)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS quote_responses;
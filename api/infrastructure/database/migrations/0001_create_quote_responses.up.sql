@@ -0,0 +1,11 @@
+-- Quotes returned by the shipping provider. IF NOT EXISTS keeps this compatible with
+-- databases created by the previous GORM AutoMigrate setup.
+CREATE TABLE IF NOT EXISTS quote_responses (
+	id         bigserial PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	carrier    jsonb
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_responses_deleted_at ON quote_responses (deleted_at);
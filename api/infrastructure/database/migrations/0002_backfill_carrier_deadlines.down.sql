@@ -0,0 +1,2 @@
+-- The backfilled fields are additive and ignored by older versions, so there is nothing to undo.
+SELECT 1;
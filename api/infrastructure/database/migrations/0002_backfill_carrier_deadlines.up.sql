@@ -0,0 +1,25 @@
+-- Fill deadline_days (from the legacy string deadline) and estimated_delivery_date
+-- (from details.estimated_date) in carrier entries stored before those fields existed.
+-- Entries that already have the fields are left untouched.
+UPDATE quote_responses
+SET carrier = (
+	SELECT jsonb_agg(
+		CASE
+			WHEN elem ? 'deadline_days' THEN elem
+			ELSE elem
+				|| CASE WHEN elem->>'deadline' ~ '^[0-9]+$'
+					THEN jsonb_build_object('deadline_days', (elem->>'deadline')::int)
+					ELSE jsonb_build_object('deadline_days', 0) END
+				|| CASE WHEN elem->'details'->>'estimated_date' ~ '^[0-9]{4}-[0-9]{2}-[0-9]{2}$'
+					THEN jsonb_build_object('estimated_delivery_date', (elem->'details'->>'estimated_date') || 'T00:00:00Z')
+					ELSE '{}'::jsonb END
+		END
+		ORDER BY ord
+	)
+	FROM jsonb_array_elements(carrier) WITH ORDINALITY AS t(elem, ord)
+)
+WHERE jsonb_typeof(carrier) = 'array'
+	AND EXISTS (
+		SELECT 1 FROM jsonb_array_elements(carrier) AS e(elem)
+		WHERE NOT elem ? 'deadline_days'
+	);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_quote_responses_recipient_zipcode;
+
+ALTER TABLE quote_responses DROP COLUMN IF EXISTS recipient_zipcode;
@@ -0,0 +1,3 @@
+ALTER TABLE quote_responses ADD COLUMN IF NOT EXISTS recipient_zipcode varchar(8);
+
+CREATE INDEX IF NOT EXISTS idx_quote_responses_recipient_zipcode ON quote_responses (recipient_zipcode);
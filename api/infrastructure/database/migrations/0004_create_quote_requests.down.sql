@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quote_request_volumes;
+
+DROP TABLE IF EXISTS quote_requests;
@@ -0,0 +1,37 @@
+-- Requests that originated each quote, with their volumes
+CREATE TABLE IF NOT EXISTS quote_requests (
+	id                          bigserial PRIMARY KEY,
+	quote_response_id           bigint NOT NULL,
+	recipient_zipcode           varchar(8) NOT NULL,
+	recipient_type              bigint NOT NULL DEFAULT 0,
+	recipient_registered_number varchar(14),
+	volume_count                bigint,
+	total_weight                decimal,
+	total_value                 decimal,
+	total_cubage                decimal,
+	created_at                  timestamptz,
+	CONSTRAINT fk_quote_responses_request FOREIGN KEY (quote_response_id)
+		REFERENCES quote_responses (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quote_requests_quote_response_id ON quote_requests (quote_response_id);
+CREATE INDEX IF NOT EXISTS idx_quote_requests_recipient_zipcode ON quote_requests (recipient_zipcode);
+
+CREATE TABLE IF NOT EXISTS quote_request_volumes (
+	id                           bigserial PRIMARY KEY,
+	quote_request_id             bigint NOT NULL,
+	dispatcher_registered_number varchar(14),
+	dispatcher_zipcode           varchar(8),
+	category                     bigint,
+	amount                       bigint,
+	unitary_weight               decimal,
+	price                        decimal,
+	sku                          text,
+	height                       decimal,
+	width                        decimal,
+	length                       decimal,
+	CONSTRAINT fk_quote_requests_volumes FOREIGN KEY (quote_request_id)
+		REFERENCES quote_requests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_request_volumes_quote_request_id ON quote_request_volumes (quote_request_id);
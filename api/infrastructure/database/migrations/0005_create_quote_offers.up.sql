@@ -0,0 +1,33 @@
+-- One row per carrier offer, so metrics can be aggregated in SQL instead of
+-- unpacking the carrier JSONB of every quote
+CREATE TABLE IF NOT EXISTS quote_offers (
+	id            bigserial PRIMARY KEY,
+	quote_id      bigint NOT NULL,
+	carrier       text NOT NULL,
+	service       text,
+	price         decimal NOT NULL,
+	deadline_days bigint NOT NULL DEFAULT 0,
+	created_at    timestamptz NOT NULL,
+	CONSTRAINT fk_quote_responses_offers FOREIGN KEY (quote_id)
+		REFERENCES quote_responses (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_offers_quote_id ON quote_offers (quote_id);
+CREATE INDEX IF NOT EXISTS idx_quote_offers_carrier ON quote_offers (carrier);
+CREATE INDEX IF NOT EXISTS idx_quote_offers_created_at ON quote_offers (created_at);
+
+-- Backfill the offers of quotes stored before this table existed
+INSERT INTO quote_offers (quote_id, carrier, service, price, deadline_days, created_at)
+SELECT q.id,
+	offer->>'name',
+	offer->>'service',
+	COALESCE((offer->>'price')::decimal, 0),
+	CASE WHEN offer->>'deadline_days' ~ '^[0-9]+$' THEN (offer->>'deadline_days')::bigint ELSE 0 END,
+	COALESCE(q.created_at, now())
+FROM quote_responses AS q
+CROSS JOIN LATERAL jsonb_array_elements(
+	CASE WHEN jsonb_typeof(q.carrier) = 'array' THEN q.carrier ELSE '[]'::jsonb END
+) AS offer
+WHERE q.deleted_at IS NULL
+	AND offer->>'name' IS NOT NULL
+	AND NOT EXISTS (SELECT 1 FROM quote_offers AS o WHERE o.quote_id = q.id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_quote_offers_recipient_zipcode;
+
+ALTER TABLE quote_offers DROP COLUMN IF EXISTS recipient_zipcode;
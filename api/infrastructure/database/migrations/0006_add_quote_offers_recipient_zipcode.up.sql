@@ -0,0 +1,11 @@
+-- Destination of each offer, copied from its quote so regional metrics need no join
+ALTER TABLE quote_offers ADD COLUMN IF NOT EXISTS recipient_zipcode varchar(8);
+
+UPDATE quote_offers AS o
+SET recipient_zipcode = q.recipient_zipcode
+FROM quote_responses AS q
+WHERE q.id = o.quote_id
+	AND o.recipient_zipcode IS NULL
+	AND q.recipient_zipcode IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quote_offers_recipient_zipcode ON quote_offers (recipient_zipcode);
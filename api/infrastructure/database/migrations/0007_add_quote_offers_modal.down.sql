@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_quote_offers_modal;
+
+ALTER TABLE quote_offers DROP COLUMN IF EXISTS modal;
@@ -0,0 +1,17 @@
+-- Transport modal of each offer, from details.modal of the stored carrier entries
+ALTER TABLE quote_offers ADD COLUMN IF NOT EXISTS modal text NOT NULL DEFAULT '';
+
+UPDATE quote_offers AS o
+SET modal = offer->'details'->>'modal'
+FROM quote_responses AS q
+CROSS JOIN LATERAL jsonb_array_elements(
+	CASE WHEN jsonb_typeof(q.carrier) = 'array' THEN q.carrier ELSE '[]'::jsonb END
+) AS offer
+WHERE q.id = o.quote_id
+	AND o.modal = ''
+	AND o.carrier = offer->>'name'
+	AND o.service IS NOT DISTINCT FROM offer->>'service'
+	AND o.price = COALESCE((offer->>'price')::decimal, 0)
+	AND offer->'details'->>'modal' IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_quote_offers_modal ON quote_offers (modal);
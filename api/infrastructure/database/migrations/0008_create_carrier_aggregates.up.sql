@@ -0,0 +1,37 @@
+-- Running per-carrier totals, updated with every stored quote, that serve the metrics
+-- of all quotes without scanning quote_offers
+CREATE TABLE IF NOT EXISTS carrier_aggregates (
+	carrier             text PRIMARY KEY,
+	quote_count         bigint NOT NULL DEFAULT 0,
+	offer_count         bigint NOT NULL DEFAULT 0,
+	total_price         decimal NOT NULL DEFAULT 0,
+	total_price_squares decimal NOT NULL DEFAULT 0,
+	min_price           decimal NOT NULL,
+	max_price           decimal NOT NULL,
+	total_deadline_days bigint NOT NULL DEFAULT 0,
+	min_deadline_days   bigint NOT NULL,
+	max_deadline_days   bigint NOT NULL,
+	cheapest_count      bigint NOT NULL DEFAULT 0,
+	updated_at          timestamptz
+);
+
+-- Seed the totals from the offers stored so far
+INSERT INTO carrier_aggregates (carrier, quote_count, offer_count, total_price, total_price_squares,
+	min_price, max_price, total_deadline_days, min_deadline_days, max_deadline_days, cheapest_count, updated_at)
+SELECT carrier,
+	COUNT(DISTINCT quote_id),
+	COUNT(*),
+	SUM(price),
+	SUM(price * price),
+	MIN(price),
+	MAX(price),
+	SUM(deadline_days),
+	MIN(deadline_days),
+	MAX(deadline_days),
+	COUNT(DISTINCT quote_id) FILTER (WHERE price = quote_min_price),
+	now()
+FROM (
+	SELECT *, MIN(price) OVER (PARTITION BY quote_id) AS quote_min_price FROM quote_offers
+) AS offers
+GROUP BY carrier
+ON CONFLICT (carrier) DO NOTHING;
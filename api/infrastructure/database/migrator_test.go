@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMigrationFS() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/0001_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id bigserial PRIMARY KEY);")},
+		"migrations/0001_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+		"migrations/0002_add_name.up.sql":        {Data: []byte("ALTER TABLE things ADD COLUMN name text;")},
+		"migrations/0002_add_name.down.sql":      {Data: []byte("ALTER TABLE things DROP COLUMN name;")},
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migrations must be numbered without gaps")
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	missingDown := fstest.MapFS{
+		"migrations/0001_create_things.up.sql": {Data: []byte("CREATE TABLE things ();")},
+	}
+	_, err := loadMigrations(missingDown)
+	assert.ErrorContains(t, err, "must have both up and down files")
+
+	badName := fstest.MapFS{
+		"migrations/create_things.sql": {Data: []byte("CREATE TABLE things ();")},
+	}
+	_, err = loadMigrations(badName)
+	assert.ErrorContains(t, err, "invalid migration file name")
+
+	conflicting := newTestMigrationFS()
+	conflicting["migrations/0002_add_title.down.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	_, err = loadMigrations(conflicting)
+	assert.ErrorContains(t, err, "conflicting names")
+}
+
+func expectLockAndTable(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_UpAppliesPendingMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := newMigrator(db, newTestMigrationFS())
+	assert.NoError(t, err)
+
+	expectLockAndTable(mock)
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE things ADD COLUMN name text;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)")).
+		WithArgs(2, "add_name").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, "0002_add_name", applied[0].String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpRollsBackFailedMigration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := newMigrator(db, newTestMigrationFS())
+	assert.NoError(t, err)
+
+	expectLockAndTable(mock)
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE things")).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+
+	assert.ErrorContains(t, err, "error running migration 0001_create_things")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_DownRevertsNewestFirst(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := newMigrator(db, newTestMigrationFS())
+	assert.NoError(t, err)
+
+	expectLockAndTable(mock)
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE things DROP COLUMN name;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	reverted, err := migrator.Down(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = migrator.Down(context.Background(), 0)
+	assert.Error(t, err)
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := newMigrator(db, newTestMigrationFS())
+	assert.NoError(t, err)
+
+	appliedAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, appliedAt))
+
+	statuses, err := migrator.Status(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, appliedAt, *statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
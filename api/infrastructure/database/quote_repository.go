@@ -2,9 +2,13 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type QuoteRepositoryImpl struct {
@@ -17,25 +21,155 @@ func NewQuoteRepository(db *gorm.DB) domain.QuoteRepository {
 	}
 }
 
-func (r *QuoteRepositoryImpl) SaveQuote(ctx context.Context, quote *domain.QuoteResponse) error {
-	result := r.db.Create(quote)
-	return result.Error
+func (r *QuoteRepositoryImpl) SaveQuote(ctx context.Context, quote *domain.QuoteResponse, request domain.QuoteRequest) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(quote).Error; err != nil {
+			return err
+		}
+
+		if offers := quote.Offers(); len(offers) > 0 {
+			if err := tx.Create(&offers).Error; err != nil {
+				return err
+			}
+			if err := addCarrierAggregates(tx, quote.CarrierAggregates()); err != nil {
+				return err
+			}
+		}
+
+		storedRequest := domain.NewStoredQuoteRequest(request)
+		storedRequest.QuoteResponseID = quote.ID
+		return tx.Create(&storedRequest).Error
+	})
+	if err != nil {
+		return domain.NewPersistenceError(err)
+	}
+	return nil
+}
+
+// addCarrierAggregates adds the quote's totals to the running per-carrier aggregates.
+// Rows are upserted in carrier order so concurrent saves lock them consistently.
+func addCarrierAggregates(tx *gorm.DB, aggregates []domain.CarrierAggregate) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "carrier"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quote_count":         gorm.Expr("carrier_aggregates.quote_count + excluded.quote_count"),
+			"offer_count":         gorm.Expr("carrier_aggregates.offer_count + excluded.offer_count"),
+			"total_price":         gorm.Expr("carrier_aggregates.total_price + excluded.total_price"),
+			"total_price_squares": gorm.Expr("carrier_aggregates.total_price_squares + excluded.total_price_squares"),
+			"min_price":           gorm.Expr("LEAST(carrier_aggregates.min_price, excluded.min_price)"),
+			"max_price":           gorm.Expr("GREATEST(carrier_aggregates.max_price, excluded.max_price)"),
+			"total_deadline_days": gorm.Expr("carrier_aggregates.total_deadline_days + excluded.total_deadline_days"),
+			"min_deadline_days":   gorm.Expr("LEAST(carrier_aggregates.min_deadline_days, excluded.min_deadline_days)"),
+			"max_deadline_days":   gorm.Expr("GREATEST(carrier_aggregates.max_deadline_days, excluded.max_deadline_days)"),
+			"cheapest_count":      gorm.Expr("carrier_aggregates.cheapest_count + excluded.cheapest_count"),
+			"updated_at":          gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(&aggregates).Error
 }
 
 func (r *QuoteRepositoryImpl) GetLastQuotes(ctx context.Context, limit int) ([]domain.QuoteResponse, error) {
 	var quotes []domain.QuoteResponse
-	
+
 	query := r.db.Order("created_at DESC")
-	
+
 	if limit > 0 {
 		query = query.Limit(limit)
 	}
-	
+
 	result := query.Find(&quotes)
-	
+
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, domain.NewPersistenceError(result.Error)
+	}
+
+	return quotes, nil
+}
+
+func (r *QuoteRepositoryImpl) ListQuotes(ctx context.Context, filter domain.QuoteFilter) ([]domain.QuoteResponse, error) {
+	var quotes []domain.QuoteResponse
+
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Limit(filter.Limit)
+	query = filterQuotes(query, filter.From, filter.To, filter.CarrierName, filter.RecipientZipcode)
+
+	if filter.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.ID)
+	}
+
+	if err := query.Find(&quotes).Error; err != nil {
+		return nil, domain.NewPersistenceError(err)
 	}
-	
+
 	return quotes, nil
-} 
\ No newline at end of file
+}
+
+// ExportQuotes walks the quotes through a database cursor, so only the current row is held in memory
+func (r *QuoteRepositoryImpl) ExportQuotes(ctx context.Context, filter domain.QuoteExportFilter, fn func(domain.QuoteResponse) error) error {
+	query := r.db.WithContext(ctx).Model(&domain.QuoteResponse{}).Order("created_at, id")
+	query = filterQuotes(query, filter.From, filter.To, filter.CarrierName, filter.RecipientZipcode)
+
+	rows, err := query.Rows()
+	if err != nil {
+		return domain.NewPersistenceError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var quote domain.QuoteResponse
+		if err := query.ScanRows(rows, &quote); err != nil {
+			return domain.NewPersistenceError(err)
+		}
+		if err := fn(quote); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return domain.NewPersistenceError(err)
+	}
+
+	return nil
+}
+
+// filterQuotes applies the history filters shared by the listing and the export
+func filterQuotes(query *gorm.DB, from, to *time.Time, carrierName, recipientZipcode string) *gorm.DB {
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at < ?", *to)
+	}
+	if carrierName != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements(carrier) AS offer WHERE lower(offer->>'name') = lower(?))", carrierName)
+	}
+	if recipientZipcode != "" {
+		query = query.Where("recipient_zipcode = ?", recipientZipcode)
+	}
+	return query
+}
+
+func (r *QuoteRepositoryImpl) GetQuote(ctx context.Context, id uint) (*domain.QuoteResponse, error) {
+	var quote domain.QuoteResponse
+
+	err := r.db.WithContext(ctx).Preload("Request.Volumes").First(&quote, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("quote %d not found", id))
+	}
+	if err != nil {
+		return nil, domain.NewPersistenceError(err)
+	}
+
+	return &quote, nil
+}
+
+func (r *QuoteRepositoryImpl) GetQuoteRequest(ctx context.Context, quoteID uint) (*domain.StoredQuoteRequest, error) {
+	var request domain.StoredQuoteRequest
+
+	err := r.db.WithContext(ctx).Preload("Volumes").Where("quote_response_id = ?", quoteID).First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.NewNotFoundError(fmt.Sprintf("request for quote %d not found", quoteID))
+	}
+	if err != nil {
+		return nil, domain.NewPersistenceError(err)
+	}
+
+	return &request, nil
+}
@@ -2,6 +2,7 @@ package database_test
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"testing"
 	"time"
@@ -32,9 +33,6 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 }
 
 func TestQuoteRepository_SaveQuote(t *testing.T) {
-	// Skip this test in CI as it requires a more complex GORM mock setup
-	t.Skip("Skipping test that requires complex GORM mock setup")
-
 	// Setup mock DB
 	db, mock := setupMockDB(t)
 
@@ -50,24 +48,68 @@ func TestQuoteRepository_SaveQuote(t *testing.T) {
 		},
 		Carriers: []domain.Carrier{
 			{
-				Name:     "EXPRESSO FR",
-				Service:  "Rodoviário",
-				Deadline: "3",
-				Price:    17.0,
+				Name:         "EXPRESSO FR",
+				Service:      "Rodoviário",
+				Deadline:     "3",
+				DeadlineDays: 3,
+				Price:        17.0,
 			},
 		},
+		RecipientZipcode: "01311000",
 	}
 
-	// Setup expectations - simplified, actual SQL would be more complex with GORM
+	request := domain.QuoteRequest{}
+	request.Recipient.Address.Zipcode = "01311000"
+	request.Volumes = []domain.Volume{
+		{Category: 7, Amount: 2, UnitaryWeight: 5.0, Price: 100.0, SKU: "abc-teste-123", Height: 0.5, Width: 0.2, Length: 0.1},
+	}
+
+	// The response, its offers, the request and its volumes are stored in a single transaction
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "quote_responses"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "quote_offers" ("quote_id","carrier","service","modal","price","deadline_days","recipient_zipcode","created_at")`)).
+		WithArgs(42, "EXPRESSO FR", "Rodoviário", "", 17.0, 3, "01311000", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	// Running per-carrier totals are updated in the same transaction
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "carrier_aggregates"`)+`.*`+
+		regexp.QuoteMeta(`ON CONFLICT ("carrier") DO UPDATE SET "cheapest_count"=carrier_aggregates.cheapest_count + excluded.cheapest_count`)).
+		WithArgs("EXPRESSO FR", 1, 1, 17.0, 289.0, 17.0, 17.0, 3, 3, 3, 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "quote_requests"`)).
+		WithArgs(42, "01311000", 0, "", 2, 10.0, 200.0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "quote_request_volumes"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
 	// Call repository method
-	err := repo.SaveQuote(context.Background(), quote)
+	err := repo.SaveQuote(context.Background(), quote, request)
 
 	// Assertions
 	assert.NoError(t, err)
+	assert.Equal(t, uint(42), quote.ID)
+	assert.Nil(t, quote.Request)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuoteRepository_SaveQuoteRollsBack(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	request := domain.QuoteRequest{}
+	request.Recipient.Address.Zipcode = "01311000"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "quote_responses"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "quote_requests"`)).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err := repo.SaveQuote(context.Background(), &domain.QuoteResponse{}, request)
+
+	assert.True(t, errors.Is(err, domain.ErrPersistenceFailure))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -96,3 +138,164 @@ func TestQuoteRepository_GetLastQuotes(t *testing.T) {
 	assert.Len(t, quotes, 2)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestQuoteRepository_ListQuotes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := &domain.QuoteCursor{CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), ID: 42}
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "carrier", "recipient_zipcode"}).
+		AddRow(41, cursor.CreatedAt, cursor.CreatedAt, nil, `[{"name":"Correios","service":"SEDEX","deadline":"1","price":20.99}]`, "01311000")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_responses" WHERE created_at >= $1 AND EXISTS (SELECT 1 FROM jsonb_array_elements(carrier) AS offer WHERE lower(offer->>'name') = lower($2)) AND recipient_zipcode = $3 AND (created_at, id) < ($4, $5) AND "quote_responses"."deleted_at" IS NULL ORDER BY created_at DESC, id DESC LIMIT 21`)).
+		WithArgs(from, "correios", "01311000", cursor.CreatedAt, cursor.ID).
+		WillReturnRows(rows)
+
+	quotes, err := repo.ListQuotes(context.Background(), domain.QuoteFilter{
+		From:             &from,
+		CarrierName:      "correios",
+		RecipientZipcode: "01311000",
+		Cursor:           cursor,
+		Limit:            21,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, quotes, 1)
+	assert.Equal(t, uint(41), quotes[0].ID)
+	assert.Equal(t, "Correios", quotes[0].Carriers[0].Name)
+	assert.Equal(t, "01311000", quotes[0].RecipientZipcode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuoteRepository_ExportQuotes(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "carrier", "recipient_zipcode"}).
+		AddRow(41, createdAt, createdAt, nil, `[{"name":"Correios","service":"SEDEX","deadline":"1","price":20.99}]`, "01311000").
+		AddRow(42, createdAt, createdAt, nil, `[{"name":"EXPRESSO FR","service":"Rodoviário","deadline":"3","price":17}]`, "01311000")
+
+	// Quotes are read oldest first, without a limit
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_responses" WHERE created_at >= $1 AND recipient_zipcode = $2 AND "quote_responses"."deleted_at" IS NULL ORDER BY created_at, id`)).
+		WithArgs(from, "01311000").
+		WillReturnRows(rows)
+
+	var ids []uint
+	err := repo.ExportQuotes(context.Background(), domain.QuoteExportFilter{From: &from, RecipientZipcode: "01311000"}, func(quote domain.QuoteResponse) error {
+		ids = append(ids, quote.ID)
+		assert.Len(t, quote.Carriers, 1)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{41, 42}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuoteRepository_ExportQuotesStopsOnCallbackError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "carrier"}).
+		AddRow(1, now, now, nil, `[]`).
+		AddRow(2, now, now, nil, `[]`)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_responses"`)).WillReturnRows(rows)
+
+	writeErr := errors.New("client went away")
+	calls := 0
+	err := repo.ExportQuotes(context.Background(), domain.QuoteExportFilter{}, func(domain.QuoteResponse) error {
+		calls++
+		return writeErr
+	})
+
+	// The callback error is returned as is, not as a persistence failure
+	assert.Equal(t, writeErr, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestQuoteRepository_ExportQuotesError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_responses"`)).WillReturnError(errors.New("db down"))
+
+	err := repo.ExportQuotes(context.Background(), domain.QuoteExportFilter{}, func(domain.QuoteResponse) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, domain.ErrPersistenceFailure)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuoteRepository_GetQuote(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "carrier"}).
+		AddRow(7, now, now, nil, `[{"name":"EXPRESSO FR","service":"Rodoviário","deadline":"3","price":17}]`)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_responses" WHERE "quote_responses"."id" = $1`)).
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	// The originating request and its volumes are preloaded
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_requests" WHERE "quote_requests"."quote_response_id" = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "quote_response_id", "recipient_zipcode", "recipient_type", "volume_count", "total_weight", "total_value", "total_cubage"}).
+			AddRow(3, 7, "01311000", 0, 2, 10.0, 200.0, 0.02))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_request_volumes" WHERE "quote_request_volumes"."quote_request_id" = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "quote_request_id", "category", "amount", "unitary_weight", "price"}).
+			AddRow(1, 3, 7, 2, 5.0, 100.0))
+
+	quote, err := repo.GetQuote(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), quote.ID)
+	assert.Equal(t, 17.0, quote.Carriers[0].Price)
+	assert.Equal(t, "01311000", quote.Request.RecipientZipcode)
+	assert.Equal(t, 200.0, quote.Request.TotalValue)
+	assert.Len(t, quote.Request.Volumes, 1)
+	assert.Equal(t, 2, quote.Request.Volumes[0].Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuoteRepository_GetQuoteNotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_responses"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	quote, err := repo.GetQuote(context.Background(), 99)
+
+	assert.Nil(t, quote)
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuoteRepository_GetQuoteRequest(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := database.NewQuoteRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_requests" WHERE quote_response_id = $1 ORDER BY "quote_requests"."id" LIMIT 1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "quote_response_id", "recipient_zipcode", "volume_count"}).AddRow(3, 7, "01311000", 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "quote_request_volumes" WHERE "quote_request_volumes"."quote_request_id" = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "quote_request_id", "amount"}).AddRow(1, 3, 2))
+
+	request, err := repo.GetQuoteRequest(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), request.QuoteResponseID)
+	assert.Equal(t, 2, request.VolumeCount)
+	assert.Len(t, request.Volumes, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
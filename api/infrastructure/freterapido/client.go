@@ -0,0 +1,357 @@
+package freterapido
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// StatusError is returned when the Frete Rápido API answers with a non-200 status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received non-200 response status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the status is worth another attempt (5xx or 429)
+func (e *StatusError) Retryable() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests
+}
+
+// Client is the Frete Rápido implementation of domain.ShippingProvider
+type Client struct {
+	config     config.FreteRapidoConfig
+	httpClient *http.Client
+}
+
+// NewClient creates a Frete Rápido client. A nil httpClient uses http.DefaultClient.
+func NewClient(cfg config.FreteRapidoConfig, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		config:     cfg,
+		httpClient: httpClient,
+	}
+}
+
+func (c *Client) Quote(ctx context.Context, request domain.QuoteRequest) (*domain.QuoteResponse, error) {
+	frRequest := c.prepareFRRequest(request)
+
+	frResponse, err := c.simulateWithRetry(ctx, frRequest)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return transformResponse(frResponse), nil
+}
+
+// simulateWithRetry calls the simulation endpoint under an overall deadline derived from ctx,
+// retrying network errors, 5xx and 429 responses with jittered exponential backoff
+func (c *Client) simulateWithRetry(ctx context.Context, request domain.FreteRapidoRequest) (*domain.FreteRapidoResponse, error) {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		frResponse, err := c.attempt(ctx, request)
+		if err == nil {
+			return frResponse, nil
+		}
+
+		if attempt >= c.config.MaxRetries || !isRetryable(ctx, err) {
+			return nil, err
+		}
+
+		delay := c.backoff(attempt)
+		log.Printf("FreteRapido attempt %d failed, retrying in %s: %v", attempt+1, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, request domain.FreteRapidoRequest) (*domain.FreteRapidoResponse, error) {
+	if c.config.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.AttemptTimeout)
+		defer cancel()
+	}
+	return c.simulate(ctx, request)
+}
+
+// backoff returns a random delay in [0, min(BackoffMax, BackoffInitial*2^attempt)] (full jitter)
+func (c *Client) backoff(attempt int) time.Duration {
+	if c.config.BackoffInitial <= 0 {
+		return 0
+	}
+
+	ceiling := c.config.BackoffInitial << attempt
+	if ceiling <= 0 || (c.config.BackoffMax > 0 && ceiling > c.config.BackoffMax) {
+		ceiling = c.config.BackoffMax
+	}
+
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// classifyError maps upstream failures to domain errors: a 4xx (other than 429) means the
+// upstream refused this request, anything else means it could not serve it right now
+func classifyError(err error) error {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && !statusErr.Retryable() {
+		return domain.NewUpstreamRejectedError(err)
+	}
+	return domain.NewUpstreamUnavailableError(err)
+}
+
+// isRetryable retries upstream 5xx/429 and transport failures, but never once the caller's context is done
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Retryable()
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+func (c *Client) prepareFRRequest(request domain.QuoteRequest) domain.FreteRapidoRequest {
+	frRequest := domain.FreteRapidoRequest{}
+
+	frRequest.Shipper.RegisteredNumber = c.config.RegisteredNumber
+	frRequest.Shipper.Token = c.config.Token
+	frRequest.Shipper.PlatformCode = c.config.PlatformCode
+
+	frRequest.Recipient.Type = request.Recipient.Type
+	frRequest.Recipient.RegisteredNumber, frRequest.Recipient.StateInscription = request.RecipientDocuments()
+	frRequest.Recipient.Country = "BRA"
+
+	zipcodeInt, _ := strconv.Atoi(request.Recipient.Address.Zipcode)
+	frRequest.Recipient.Zipcode = zipcodeInt
+
+	// Top-level volumes ship from the configured default dispatcher
+	if len(request.Volumes) > 0 {
+		frRequest.Dispatchers = append(frRequest.Dispatchers, c.prepareDispatcher(domain.Dispatcher{Volumes: request.Volumes}))
+	}
+	for _, dispatcher := range request.Dispatchers {
+		frRequest.Dispatchers = append(frRequest.Dispatchers, c.prepareDispatcher(dispatcher))
+	}
+
+	frRequest.SimulationType = request.SimulationTypes()
+	frRequest.Returns.Composition = request.Returns.Composition
+	frRequest.Returns.Volumes = request.Returns.Volumes
+	frRequest.Returns.AppliedRules = request.Returns.AppliedRules
+
+	return frRequest
+}
+
+func (c *Client) simulate(ctx context.Context, request domain.FreteRapidoRequest) (*domain.FreteRapidoResponse, error) {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	log.Printf("Calling FreteRapido API at: %s", c.config.APIURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.APIURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error executing HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody := make([]byte, 1024)
+		n, _ := resp.Body.Read(respBody)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(respBody[:n])}
+	}
+
+	var frResponse domain.FreteRapidoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&frResponse); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return &frResponse, nil
+}
+
+// prepareDispatcher converts a dispatcher, falling back to the configured registered number and zipcode
+func (c *Client) prepareDispatcher(dispatcher domain.Dispatcher) domain.FreteRapidoDispatcher {
+	registeredNumber := dispatcher.RegisteredNumber
+	if registeredNumber == "" {
+		registeredNumber = c.config.RegisteredNumber
+	}
+	zipcode := dispatcher.Zipcode
+	if zipcode == "" {
+		zipcode = c.config.DispatcherZipcode
+	}
+	zipcodeInt, _ := strconv.Atoi(zipcode)
+
+	frDispatcher := domain.FreteRapidoDispatcher{
+		RegisteredNumber: registeredNumber,
+		Zipcode:          zipcodeInt,
+		Volumes:          []domain.FreteRapidoVolume{},
+	}
+
+	for _, vol := range dispatcher.Volumes {
+		frVolume := domain.FreteRapidoVolume{
+			Amount:        vol.Amount,
+			Category:      strconv.Itoa(vol.Category),
+			Sku:           vol.SKU,
+			Height:        vol.Height,
+			Width:         vol.Width,
+			Length:        vol.Length,
+			UnitaryWeight: vol.UnitaryWeight,
+			UnitaryPrice:  vol.Price,
+		}
+		frDispatcher.Volumes = append(frDispatcher.Volumes, frVolume)
+	}
+
+	return frDispatcher
+}
+
+func transformComposition(composition *domain.FreteRapidoComposition) *domain.OfferComposition {
+	if composition == nil {
+		return nil
+	}
+	return &domain.OfferComposition{
+		FreightWeight:       composition.FreightWeight,
+		FreightWeightExcess: composition.FreightWeightExcess,
+		FreightWeightVolume: composition.FreightWeightVolume,
+		FreightVolume:       composition.FreightVolume,
+		FreightMinimum:      composition.FreightMinimum,
+		FreightInvoice:      composition.FreightInvoice,
+		SubTotal1:           composition.SubTotal1,
+		SubTotal2:           composition.SubTotal2,
+		SubTotal3:           composition.SubTotal3,
+	}
+}
+
+func transformVolumes(frVolumes []domain.FreteRapidoVolume) []domain.OfferVolume {
+	if len(frVolumes) == 0 {
+		return nil
+	}
+	volumes := make([]domain.OfferVolume, 0, len(frVolumes))
+	for _, vol := range frVolumes {
+		volumes = append(volumes, domain.OfferVolume{
+			Category:      vol.Category,
+			Amount:        vol.Amount,
+			SKU:           vol.Sku,
+			Height:        vol.Height,
+			Width:         vol.Width,
+			Length:        vol.Length,
+			UnitaryPrice:  vol.UnitaryPrice,
+			UnitaryWeight: vol.UnitaryWeight,
+		})
+	}
+	return volumes
+}
+
+func transformAppliedRules(rules json.RawMessage) json.RawMessage {
+	if len(rules) == 0 || string(rules) == "null" {
+		return nil
+	}
+	return rules
+}
+
+func transformResponse(frResponse *domain.FreteRapidoResponse) *domain.QuoteResponse {
+	response := &domain.QuoteResponse{
+		Carriers: []domain.Carrier{},
+	}
+
+	for _, dispatcher := range frResponse.Dispatchers {
+		origin := &domain.CarrierDispatcher{
+			ID:               dispatcher.ID,
+			RegisteredNumber: dispatcher.RegisteredNumberDispatcher,
+			Zipcode:          fmt.Sprintf("%08d", dispatcher.ZipcodeOrigin),
+		}
+		volumes := transformVolumes(dispatcher.Volumes)
+
+		for _, offer := range dispatcher.Offers {
+			carrier := domain.Carrier{
+				Name:                  offer.Carrier.Name,
+				Service:               offer.Service,
+				Deadline:              strconv.Itoa(offer.DeliveryTime.Days),
+				Price:                 offer.FinalPrice,
+				DeadlineDays:          offer.DeliveryTime.Days,
+				EstimatedDeliveryDate: parseEstimatedDate(offer.DeliveryTime.EstimatedDate),
+				Dispatcher:            origin,
+				SimulationType:        offer.SimulationType,
+				Composition:           transformComposition(offer.Composition),
+				Volumes:               volumes,
+				AppliedRules:          transformAppliedRules(offer.AppliedRules),
+				Details: &domain.CarrierDetails{
+					RegisteredNumber: offer.Carrier.RegisteredNumber,
+					CompanyName:      offer.Carrier.CompanyName,
+					Logo:             offer.Carrier.Logo,
+					CostPrice:        offer.CostPrice,
+					FinalPrice:       offer.FinalPrice,
+					Weights: domain.CarrierWeights{
+						Real:  offer.Weights.Real,
+						Cubed: offer.Weights.Cubed,
+						Used:  offer.Weights.Used,
+					},
+					EstimatedDate: offer.DeliveryTime.EstimatedDate,
+					Modal:         offer.Modal,
+					HomeDelivery:  offer.HomeDelivery,
+					Expiration:    offer.Expiration,
+				},
+			}
+			response.Carriers = append(response.Carriers, carrier)
+
+			// The quote is only as fresh as its earliest expiring offer
+			if !offer.Expiration.IsZero() && (response.ExpiresAt.IsZero() || offer.Expiration.Before(response.ExpiresAt)) {
+				response.ExpiresAt = offer.Expiration
+			}
+		}
+	}
+
+	return response
+}
+
+// estimatedDateLayouts are the formats the upstream has been seen using for delivery dates
+var estimatedDateLayouts = []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04:05"}
+
+// parseEstimatedDate parses the upstream estimated delivery date, returning nil when absent or unparseable
+func parseEstimatedDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, layout := range estimatedDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date
+		}
+	}
+	log.Printf("WARNING: could not parse estimated delivery date %q", value)
+	return nil
+}
@@ -0,0 +1,424 @@
+package freterapido
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+const simulateResponse = `{
+	"dispatchers": [{
+		"id": "dispatcher-1",
+		"registered_number_dispatcher": "25438296000158",
+		"zipcode_origin": 29161376,
+		"offers": [
+			{
+				"carrier": {
+					"name": "EXPRESSO FR",
+					"registered_number": "25438296000158",
+					"company_name": "EXPRESSO FR LTDA",
+					"logo": "https://example.com/expresso.png"
+				},
+				"service": "Rodoviário",
+				"delivery_time": {"days": 3, "estimated_date": "2025-01-13"},
+				"expiration": "2025-01-10T12:00:00Z",
+				"cost_price": 15.5,
+				"final_price": 17.0,
+				"weights": {"real": 5.0, "cubed": 2.4, "used": 5.0},
+				"home_delivery": true,
+				"modal": "Rodoviário"
+			},
+			{
+				"carrier": {"name": "Correios"},
+				"service": "SEDEX",
+				"delivery_time": {"days": 1},
+				"expiration": "2025-01-08T12:00:00Z",
+				"final_price": 20.99
+			}
+		]
+	}]
+}`
+
+func newTestConfig(apiURL string) config.FreteRapidoConfig {
+	return config.FreteRapidoConfig{
+		APIURL:            apiURL,
+		Token:             "token",
+		RegisteredNumber:  "25438296000158",
+		PlatformCode:      "platform",
+		DispatcherZipcode: "29161376",
+		Timeout:           time.Second,
+		AttemptTimeout:    500 * time.Millisecond,
+		MaxRetries:        2,
+		BackoffInitial:    time.Millisecond,
+		BackoffMax:        5 * time.Millisecond,
+	}
+}
+
+func newTestRequest() domain.QuoteRequest {
+	request := domain.QuoteRequest{}
+	request.Recipient.Address.Zipcode = "01311000"
+	request.Volumes = []domain.Volume{
+		{
+			Category:      7,
+			Amount:        1,
+			UnitaryWeight: 5.0,
+			Price:         349.0,
+			SKU:           "abc-teste-123",
+			Height:        0.2,
+			Width:         0.2,
+			Length:        0.2,
+		},
+	}
+	return request
+}
+
+func TestClient_Quote(t *testing.T) {
+	var received domain.FreteRapidoRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(simulateResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Carriers, 2)
+	summary := result.WithoutDetails()
+	estimatedDeliveryDate := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+	origin := &domain.CarrierDispatcher{ID: "dispatcher-1", RegisteredNumber: "25438296000158", Zipcode: "29161376"}
+	assert.Equal(t, domain.Carrier{Name: "EXPRESSO FR", Service: "Rodoviário", Deadline: "3", Price: 17.0, DeadlineDays: 3, EstimatedDeliveryDate: &estimatedDeliveryDate, Dispatcher: origin}, summary.Carriers[0])
+	assert.Equal(t, domain.Carrier{Name: "Correios", Service: "SEDEX", Deadline: "1", Price: 20.99, DeadlineDays: 1, Dispatcher: origin}, summary.Carriers[1])
+
+	// The full offer is preserved in the details
+	assert.Equal(t, &domain.CarrierDetails{
+		RegisteredNumber: "25438296000158",
+		CompanyName:      "EXPRESSO FR LTDA",
+		Logo:             "https://example.com/expresso.png",
+		CostPrice:        15.5,
+		FinalPrice:       17.0,
+		Weights:          domain.CarrierWeights{Real: 5.0, Cubed: 2.4, Used: 5.0},
+		EstimatedDate:    "2025-01-13",
+		Modal:            "Rodoviário",
+		HomeDelivery:     true,
+		Expiration:       time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+	}, result.Carriers[0].Details)
+	assert.Equal(t, time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC), result.ExpiresAt)
+
+	// Verify the upstream request was built from the config and the quote request
+	assert.Equal(t, "token", received.Shipper.Token)
+	assert.Equal(t, "25438296000158", received.Shipper.RegisteredNumber)
+	assert.Equal(t, "platform", received.Shipper.PlatformCode)
+	assert.Equal(t, 1311000, received.Recipient.Zipcode)
+	assert.Len(t, received.Dispatchers, 1)
+	assert.Equal(t, 29161376, received.Dispatchers[0].Zipcode)
+	assert.Len(t, received.Dispatchers[0].Volumes, 1)
+	assert.Equal(t, "7", received.Dispatchers[0].Volumes[0].Category)
+	assert.Equal(t, 349.0, received.Dispatchers[0].Volumes[0].UnitaryPrice)
+}
+
+func TestClient_QuoteNon200(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "invalid zipcode"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "received non-200 response status: 400")
+	assert.ErrorIs(t, err, domain.ErrUpstreamRejected)
+
+	// 4xx responses other than 429 are not retried
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_QuoteRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(simulateResponse))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Carriers, 2)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestClient_QuoteGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+
+	var statusErr *StatusError
+	assert.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+
+	// First attempt plus MaxRetries
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestClient_QuoteAttemptTimeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Hang longer than the attempt deadline
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(simulateResponse))
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig(server.URL)
+	cfg.AttemptTimeout = 50 * time.Millisecond
+	client := NewClient(cfg, server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Carriers, 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_QuoteHonorsCallerContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := newTestConfig(server.URL)
+	cfg.MaxRetries = 100
+	cfg.BackoffInitial = 20 * time.Millisecond
+	cfg.BackoffMax = 20 * time.Millisecond
+	client := NewClient(cfg, server.Client())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := client.Quote(ctx, newTestRequest())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_QuoteInvalidJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`invalid json`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "error decoding response")
+}
+
+func TestClient_QuoteMultipleDispatchers(t *testing.T) {
+	var received domain.FreteRapidoRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"dispatchers": [
+				{"id": "d1", "registered_number_dispatcher": "25438296000158", "zipcode_origin": 29161376, "offers": [{"carrier": {"name": "Correios"}, "final_price": 20}]},
+				{"id": "d2", "registered_number_dispatcher": "11222333000181", "zipcode_origin": 1311000, "offers": [{"carrier": {"name": "Jadlog"}, "final_price": 12}]}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	request := newTestRequest()
+	request.Dispatchers = []domain.Dispatcher{
+		{RegisteredNumber: "11222333000181", Zipcode: "01311000", Volumes: request.Volumes},
+		{Volumes: request.Volumes},
+	}
+
+	result, err := client.Quote(context.Background(), request)
+
+	assert.NoError(t, err)
+
+	// Top-level volumes go to the default dispatcher, missing fields fall back to the config
+	assert.Len(t, received.Dispatchers, 3)
+	assert.Equal(t, "25438296000158", received.Dispatchers[0].RegisteredNumber)
+	assert.Equal(t, 29161376, received.Dispatchers[0].Zipcode)
+	assert.Equal(t, "11222333000181", received.Dispatchers[1].RegisteredNumber)
+	assert.Equal(t, 1311000, received.Dispatchers[1].Zipcode)
+	assert.Equal(t, "25438296000158", received.Dispatchers[2].RegisteredNumber)
+	assert.Equal(t, 29161376, received.Dispatchers[2].Zipcode)
+	assert.Len(t, received.Dispatchers[2].Volumes, 1)
+
+	// Each offer keeps its origin
+	assert.Len(t, result.Carriers, 2)
+	assert.Equal(t, &domain.CarrierDispatcher{ID: "d1", RegisteredNumber: "25438296000158", Zipcode: "29161376"}, result.Carriers[0].Dispatcher)
+	assert.Equal(t, &domain.CarrierDispatcher{ID: "d2", RegisteredNumber: "11222333000181", Zipcode: "01311000"}, result.Carriers[1].Dispatcher)
+}
+
+func TestParseEstimatedDate(t *testing.T) {
+	expected := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, &expected, parseEstimatedDate("2025-01-13"))
+	assert.Equal(t, &expected, parseEstimatedDate("2025-01-13 00:00:00"))
+	assert.Equal(t, &expected, parseEstimatedDate("2025-01-13T00:00:00Z"))
+	assert.Nil(t, parseEstimatedDate(""))
+	assert.Nil(t, parseEstimatedDate("13/01/2025"))
+}
+
+func TestClient_QuoteReturns(t *testing.T) {
+	var received domain.FreteRapidoRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"dispatchers": [{
+				"id": "d1",
+				"zipcode_origin": 29161376,
+				"volumes": [{"amount": 1, "category": "7", "sku": "abc-teste-123", "height": 0.2, "width": 0.2, "length": 0.2, "unitary_price": 349, "unitary_weight": 5}],
+				"offers": [
+					{
+						"carrier": {"name": "EXPRESSO FR"},
+						"simulation_type": 1,
+						"final_price": 17,
+						"composition": {"freight_weight": 12.5, "freight_minimum": 10, "sub_total1": {"toll": 1.5}},
+						"applied_rules": [{"type": "discount", "value": 2}]
+					},
+					{"carrier": {"name": "Correios"}, "final_price": 20.99, "applied_rules": null}
+				]
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	request := newTestRequest()
+	request.SimulationType = []int{domain.SimulationTypeFractional, domain.SimulationTypeFullLoad}
+	request.Returns = domain.QuoteReturns{Composition: true, Volumes: true, AppliedRules: true}
+
+	result, err := client.Quote(context.Background(), request)
+
+	assert.NoError(t, err)
+
+	// The options are forwarded upstream
+	assert.Equal(t, []int{0, 1}, received.SimulationType)
+	assert.True(t, received.Returns.Composition)
+	assert.True(t, received.Returns.Volumes)
+	assert.True(t, received.Returns.AppliedRules)
+
+	// And the extra structures are surfaced on each offer
+	first := result.Carriers[0]
+	assert.Equal(t, domain.SimulationTypeFullLoad, first.SimulationType)
+	assert.Equal(t, &domain.OfferComposition{FreightWeight: 12.5, FreightMinimum: 10, SubTotal1: map[string]float64{"toll": 1.5}}, first.Composition)
+	assert.Equal(t, []domain.OfferVolume{{Category: "7", Amount: 1, SKU: "abc-teste-123", Height: 0.2, Width: 0.2, Length: 0.2, UnitaryPrice: 349, UnitaryWeight: 5}}, first.Volumes)
+	assert.JSONEq(t, `[{"type": "discount", "value": 2}]`, string(first.AppliedRules))
+
+	second := result.Carriers[1]
+	assert.Nil(t, second.Composition)
+	assert.Nil(t, second.AppliedRules)
+	assert.Len(t, second.Volumes, 1)
+}
+
+func TestClient_QuoteDefaultOptions(t *testing.T) {
+	var received domain.FreteRapidoRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(simulateResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	result, err := client.Quote(context.Background(), newTestRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, received.Recipient.Type)
+	assert.Empty(t, received.Recipient.RegisteredNumber)
+	assert.Equal(t, "BRA", received.Recipient.Country)
+	assert.Equal(t, []int{0}, received.SimulationType)
+	assert.False(t, received.Returns.Composition)
+	assert.False(t, received.Returns.Volumes)
+	assert.False(t, received.Returns.AppliedRules)
+	assert.Nil(t, result.Carriers[0].Composition)
+	assert.Nil(t, result.Carriers[0].Volumes)
+}
+
+func TestClient_QuoteCompanyRecipient(t *testing.T) {
+	var received domain.FreteRapidoRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(simulateResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(newTestConfig(server.URL), server.Client())
+
+	request := newTestRequest()
+	request.Recipient.Type = domain.RecipientTypeCompany
+	request.Recipient.RegisteredNumber = "11.222.333/0001-81"
+	request.Recipient.StateInscription = "123.456.789"
+
+	_, err := client.Quote(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RecipientTypeCompany, received.Recipient.Type)
+	assert.Equal(t, "11222333000181", received.Recipient.RegisteredNumber)
+	assert.Equal(t, "123456789", received.Recipient.StateInscription)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/circuitbreaker"
+)
+
+type DiagnosticsController struct {
+	circuitBreakers []*circuitbreaker.CircuitBreaker
+}
+
+func NewDiagnosticsController(circuitBreakers ...*circuitbreaker.CircuitBreaker) *DiagnosticsController {
+	return &DiagnosticsController{
+		circuitBreakers: circuitBreakers,
+	}
+}
+
+// GetCircuitBreakers retorna o estado dos circuit breakers das integrações externas
+// @Summary Obter estado dos circuit breakers
+// @Description Retorna o estado (closed, open, half-open) e os contadores de cada circuit breaker
+// @Tags diagnóstico
+// @Produce json
+// @Success 200 {object} map[string][]circuitbreaker.Snapshot "Estado dos circuit breakers"
+// @Router /diagnostics/circuit-breakers [get]
+func (c *DiagnosticsController) GetCircuitBreakers(ctx *gin.Context) {
+	snapshots := make([]circuitbreaker.Snapshot, 0, len(c.circuitBreakers))
+	for _, cb := range c.circuitBreakers {
+		if cb != nil {
+			snapshots = append(snapshots, cb.Snapshot())
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"circuit_breakers": snapshots})
+}
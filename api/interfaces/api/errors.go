@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+const problemContentType = "application/problem+json"
+
+// statusClientClosedRequest is the non-standard status nginx records for requests whose client
+// disconnected before the response was written
+const statusClientClosedRequest = 499
+
+// ProblemDetails é o corpo de erro no formato RFC 7807 (application/problem+json)
+// @Description Detalhes de um erro da API (RFC 7807)
+type ProblemDetails struct {
+	// URI que identifica o tipo do problema
+	// @example "/problems/validation_failed"
+	Type string `json:"type"`
+	// Resumo do tipo do problema
+	// @example "Validation Failed"
+	Title string `json:"title"`
+	// Código HTTP
+	// @example 422
+	Status int `json:"status"`
+	// Explicação específica desta ocorrência
+	// @example "The quote request has invalid fields"
+	Detail string `json:"detail,omitempty"`
+	// Caminho da requisição que originou o problema
+	// @example "/quote"
+	Instance string `json:"instance,omitempty"`
+	// Código estável do erro
+	// @example "validation_failed"
+	Code domain.ErrorCode `json:"code"`
+	// Violações por campo (apenas para erros de validação)
+	Errors []domain.InputError `json:"errors,omitempty"`
+}
+
+var errorStatuses = map[domain.ErrorCode]int{
+	domain.CodeInvalidRequest:      http.StatusBadRequest,
+	domain.CodeValidationFailed:    http.StatusUnprocessableEntity,
+	domain.CodeUpstreamUnavailable: http.StatusServiceUnavailable,
+	domain.CodeUpstreamRejected:    http.StatusBadGateway,
+	domain.CodePersistenceFailure:  http.StatusInternalServerError,
+	domain.CodeNotFound:            http.StatusNotFound,
+	domain.CodeNotAcceptable:       http.StatusNotAcceptable,
+	domain.CodeTimeout:             http.StatusGatewayTimeout,
+	domain.CodeInternal:            http.StatusInternalServerError,
+}
+
+// respondError maps a (possibly wrapped) domain error to its HTTP status and writes it as
+// application/problem+json. The raw cause is only logged, never sent to the client.
+func respondError(ctx *gin.Context, err error) {
+	// A client that went away reads no response, and its disconnect is not a server failure
+	if errors.Is(err, context.Canceled) && ctx.Request.Context().Err() != nil {
+		log.Printf("INFO: %s %s: client closed the request: %v", ctx.Request.Method, ctx.Request.URL.Path, err)
+		ctx.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+
+	code := domain.ErrorCodeOf(err)
+	status, ok := errorStatuses[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	problem := ProblemDetails{
+		Type:     "/problems/" + string(code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: ctx.Request.URL.Path,
+		Code:     code,
+	}
+
+	var validationErr *domain.ValidationError
+	var domainErr *domain.Error
+	switch {
+	case errors.As(err, &validationErr):
+		problem.Detail = "The request has invalid fields"
+		problem.Errors = validationErr.Errors
+	case errors.As(err, &domainErr):
+		problem.Detail = domainErr.Message
+		if domainErr.RetryAfter > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(retryAfterSeconds(domainErr.RetryAfter)))
+		}
+	default:
+		problem.Detail = "An unexpected error occurred"
+	}
+
+	if status >= http.StatusInternalServerError {
+		log.Printf("ERROR: %s %s: %s: %v", ctx.Request.Method, ctx.Request.URL.Path, code, err)
+	} else {
+		log.Printf("WARNING: %s %s: %s: %v", ctx.Request.Method, ctx.Request.URL.Path, code, err)
+	}
+
+	ctx.Header("Content-Type", problemContentType)
+	ctx.Render(status, render.JSON{Data: problem})
+}
+
+// retryAfterSeconds rounds a delay up to whole seconds, as required by the Retry-After header
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
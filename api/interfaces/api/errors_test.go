@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func performRespondError(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/quote", nil)
+
+	respondError(ctx, err)
+	return w
+}
+
+func TestRespondError_StatusMapping(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   domain.ErrorCode
+	}{
+		{domain.NewInvalidRequestError("Invalid request format", errors.New("EOF")), http.StatusBadRequest, domain.CodeInvalidRequest},
+		{domain.QuoteRequest{}.Validate(), http.StatusUnprocessableEntity, domain.CodeValidationFailed},
+		{domain.NewUpstreamUnavailableError(errors.New("timeout")), http.StatusServiceUnavailable, domain.CodeUpstreamUnavailable},
+		{domain.NewUpstreamRejectedError(errors.New("status 400")), http.StatusBadGateway, domain.CodeUpstreamRejected},
+		{fmt.Errorf("error saving quote: %w", domain.NewPersistenceError(errors.New("db down"))), http.StatusInternalServerError, domain.CodePersistenceFailure},
+		{domain.NewNotFoundError("Quote not found"), http.StatusNotFound, domain.CodeNotFound},
+		{domain.NewNotAcceptableError("Accept must allow text/csv"), http.StatusNotAcceptable, domain.CodeNotAcceptable},
+		{domain.NewTimeoutError(context.DeadlineExceeded), http.StatusGatewayTimeout, domain.CodeTimeout},
+		{errors.New("boom"), http.StatusInternalServerError, domain.CodeInternal},
+	}
+
+	for _, tc := range cases {
+		w := performRespondError(tc.err)
+
+		assert.Equal(t, tc.status, w.Code, "code %s", tc.code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+		var problem ProblemDetails
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, tc.code, problem.Code)
+		assert.Equal(t, tc.status, problem.Status)
+		assert.Equal(t, "/problems/"+string(tc.code), problem.Type)
+		assert.Equal(t, "/quote", problem.Instance)
+	}
+}
+
+func TestRespondError_HidesRawCause(t *testing.T) {
+	cause := errors.New("received non-200 response status: 500, body: stack trace")
+	w := performRespondError(fmt.Errorf("error calling shipping provider: %w", domain.NewUpstreamUnavailableError(cause)))
+
+	assert.NotContains(t, w.Body.String(), "stack trace")
+	assert.NotContains(t, w.Body.String(), "non-200")
+}
+
+func TestRespondError_ValidationFields(t *testing.T) {
+	request := domain.QuoteRequest{}
+	w := performRespondError(request.Validate())
+
+	var problem ProblemDetails
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Len(t, problem.Errors, 2)
+	assert.Equal(t, "recipient.address.zipcode", problem.Errors[0].Field)
+	assert.Equal(t, "volumes", problem.Errors[1].Field)
+}
+
+func TestRespondError_RetryAfter(t *testing.T) {
+	err := domain.NewUpstreamUnavailableError(errors.New("circuit open"))
+	err.RetryAfter = 2500 * time.Millisecond
+
+	w := performRespondError(err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "3", w.Header().Get("Retry-After"))
+}
+
+func TestRespondError_ClientClosedRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	requestCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/quote", nil).WithContext(requestCtx)
+
+	respondError(ctx, fmt.Errorf("error saving quote: %w", domain.NewPersistenceError(context.Canceled)))
+
+	// Nobody is left to read a problem, so only the status is recorded
+	assert.Equal(t, statusClientClosedRequest, ctx.Writer.Status())
+	assert.Empty(t, w.Body.String())
+
+	// A cancellation the client did not cause is still a server failure
+	w = performRespondError(context.Canceled)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// Representations offered through the Accept header
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// negotiateFormat picks the first offered representation the Accept header allows, defaulting
+// to the first offer when the header is missing
+func negotiateFormat(ctx *gin.Context, offered ...string) (string, error) {
+	format := ctx.NegotiateFormat(offered...)
+	if format == "" {
+		return "", domain.NewNotAcceptableError("Accept must allow one of " + strings.Join(offered, ", "))
+	}
+	return format, nil
+}
+
+// rowWriter encodes rows as CSV, after a header line, or as NDJSON, one JSON object per line
+type rowWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func newRowWriter(w io.Writer, format string, header []string) (*rowWriter, error) {
+	if format == contentTypeNDJSON {
+		return &rowWriter{json: json.NewEncoder(w)}, nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	return &rowWriter{csv: writer}, nil
+}
+
+// write encodes value as a JSON line or record as a CSV line, depending on the format
+func (w *rowWriter) write(value interface{}, record []string) error {
+	if w.json != nil {
+		return w.json.Encode(value)
+	}
+	return w.csv.Write(record)
+}
+
+// flush writes any buffered CSV lines to the underlying writer
+func (w *rowWriter) flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+var metricsCSVHeader = []string{
+	"region", "carrier_name", "service", "modal", "total_quotes", "total_shipping_price",
+	"average_shipping_price", "min_shipping_price", "max_shipping_price", "median_shipping_price",
+	"p90_shipping_price", "p95_shipping_price", "stddev_shipping_price", "average_delivery_days",
+	"min_delivery_days", "max_delivery_days", "cheapest_count", "cheapest_share",
+}
+
+func metricsCSVRecord(row domain.MetricsRow) []string {
+	return []string{
+		row.Region,
+		row.CarrierName,
+		row.Service,
+		row.Modal,
+		strconv.Itoa(row.TotalQuotes),
+		formatCSVFloat(row.TotalShippingPrice),
+		formatCSVFloat(row.AverageShippingPrice),
+		formatCSVFloat(row.MinShippingPrice),
+		formatCSVFloat(row.MaxShippingPrice),
+		formatCSVOptionalFloat(row.MedianShippingPrice),
+		formatCSVOptionalFloat(row.P90ShippingPrice),
+		formatCSVOptionalFloat(row.P95ShippingPrice),
+		formatCSVFloat(row.StddevShippingPrice),
+		formatCSVFloat(row.AverageDeliveryDays),
+		strconv.Itoa(row.MinDeliveryDays),
+		strconv.Itoa(row.MaxDeliveryDays),
+		strconv.Itoa(row.CheapestCount),
+		formatCSVFloat(row.CheapestShare),
+	}
+}
+
+var quoteExportCSVHeader = []string{
+	"quote_id", "created_at", "recipient_zipcode", "carrier", "service", "modal", "price",
+	"deadline_days", "estimated_delivery_date", "cheapest", "fastest",
+}
+
+func quoteExportCSVRecord(row domain.QuoteExportRow) []string {
+	var estimatedDeliveryDate string
+	if row.EstimatedDeliveryDate != nil {
+		estimatedDeliveryDate = row.EstimatedDeliveryDate.UTC().Format(time.RFC3339)
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(row.QuoteID), 10),
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.RecipientZipcode,
+		row.Carrier,
+		row.Service,
+		row.Modal,
+		formatCSVFloat(row.Price),
+		strconv.Itoa(row.DeadlineDays),
+		estimatedDeliveryDate,
+		strconv.FormatBool(row.Cheapest),
+		strconv.FormatBool(row.Fastest),
+	}
+}
+
+func formatCSVFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatCSVOptionalFloat leaves the cell empty for values that were not computed
+func formatCSVOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatCSVFloat(*value)
+}
@@ -1,10 +1,15 @@
 package api
 
 import (
-	"fmt"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
-	"sync"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
@@ -12,81 +17,252 @@ import (
 )
 
 type MetricsController struct {
-	getMetricsUseCase *usecases.GetMetricsUseCase
+	getMetricsUseCase       *usecases.GetMetricsUseCase
+	getMetricsSeriesUseCase *usecases.GetMetricsSeriesUseCase
 }
 
-func NewMetricsController(getMetricsUseCase *usecases.GetMetricsUseCase) *MetricsController {
+func NewMetricsController(getMetricsUseCase *usecases.GetMetricsUseCase, getMetricsSeriesUseCase *usecases.GetMetricsSeriesUseCase) *MetricsController {
 	return &MetricsController{
-		getMetricsUseCase: getMetricsUseCase,
+		getMetricsUseCase:       getMetricsUseCase,
+		getMetricsSeriesUseCase: getMetricsSeriesUseCase,
 	}
 }
 
 // GetMetrics retorna métricas sobre as cotações de frete
 // @Summary Obter métricas de cotações
-// @Description Retorna métricas e estatísticas sobre as cotações de frete realizadas
+// @Description Retorna métricas e estatísticas sobre as cotações de frete realizadas, opcionalmente restritas a uma janela de tempo.
+// @Description Com Accept text/csv ou application/x-ndjson, retorna uma linha por transportadora (e por região, com group_by).
+// @Description Com percentiles=false, a mediana e os percentis são omitidos (colunas vazias no CSV) e, sem from, to, period, last_quotes e group_by (ou apenas com group_by=carrier), as métricas vêm dos agregados acumulados, sem ler as ofertas.
 // @Tags métricas
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param last_quotes query int false "Número de cotações recentes a considerar (opcional)"
+// @Param from query string false "Início do período (RFC 3339 ou AAAA-MM-DD no fuso informado)"
+// @Param to query string false "Fim do período, exclusivo (RFC 3339 ou AAAA-MM-DD, que inclui o dia inteiro)"
+// @Param period query string false "Período relativo, alternativo a from/to" Enums(last_24h, last_7d, last_30d, today, this_month)
+// @Param timezone query string false "Fuso horário IANA usado nos períodos e datas (padrão: UTC)"
+// @Param group_by query string false "Dimensões separadas por vírgula: state ou cep_prefix separa por região do destinatário; carrier (padrão), carrier_service ou modal define a chave de cada linha"
+// @Param cep_prefix_length query int false "Dígitos do prefixo do CEP com group_by=cep_prefix (1 a 5, padrão: 3)"
+// @Param percentiles query bool false "Calcula a mediana e os percentis 90 e 95 (padrão: true)"
+// @Param If-None-Match header string false "ETag de uma resposta anterior; responde 304 se as métricas não mudaram"
 // @Success 200 {object} domain.MetricsResponse "Métricas de cotações"
-// @Failure 400 {object} map[string]string "Erro de parâmetro inválido"
-// @Failure 500 {object} map[string]string "Erro interno do servidor"
+// @Header 200 {string} ETag "Identificador do conteúdo da resposta"
+// @Success 304 "Métricas inalteradas desde o ETag informado"
+// @Failure 406 {object} ProblemDetails "Nenhum formato aceito pelo cabeçalho Accept"
+// @Failure 400 {object} ProblemDetails "Erro de parâmetro inválido"
+// @Failure 422 {object} ProblemDetails "Erros de validação por campo"
+// @Failure 500 {object} ProblemDetails "Erro interno do servidor"
 // @Router /metrics [get]
 func (c *MetricsController) GetMetrics(ctx *gin.Context) {
-	// Parse last_quotes parameter
-	lastQuotesStr := ctx.Query("last_quotes")
-	lastQuotes := 0
+	format, err := negotiateFormat(ctx, contentTypeJSON, contentTypeCSV, contentTypeNDJSON)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
 
-	if lastQuotesStr != "" {
-		var err error
-		lastQuotes, err = strconv.Atoi(lastQuotesStr)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "last_quotes must be a valid integer"})
-			return
+	query, err := parseMetricsQuery(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	metrics, err := c.getMetricsUseCase.Execute(ctx, query)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	body, err := renderMetrics(metrics, format)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.Header("Vary", "Accept")
+	respondWithETag(ctx, format+"; charset=utf-8", body)
+}
+
+// renderMetrics encodes the metrics in the negotiated format. CSV and NDJSON carry only the
+// metrics rows; the window and the cheapest and most expensive prices are JSON only.
+func renderMetrics(metrics *domain.MetricsResponse, format string) ([]byte, error) {
+	if format == contentTypeJSON {
+		return json.Marshal(metrics)
+	}
+
+	var body bytes.Buffer
+	writer, err := newRowWriter(&body, format, metricsCSVHeader)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range metrics.Rows() {
+		if err := writer.write(row, metricsCSVRecord(row)); err != nil {
+			return nil, err
 		}
+	}
+	if err := writer.flush(); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
 
-		if lastQuotes < 0 {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "last_quotes must be a positive integer"})
-			return
+// respondWithETag writes body with an ETag derived from its content, or only 304 Not Modified
+// when the client already holds the same representation. Clients are asked to revalidate on
+// every use, so polling dashboards get a 304 until the metrics change.
+func respondWithETag(ctx *gin.Context, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "no-cache")
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, contentType, body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak validators match too,
+// as If-None-Match uses the weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
 		}
+	}
+	return false
+}
+
+// GetMetricsSeries retorna métricas por transportadora agrupadas em intervalos de tempo
+// @Summary Obter série temporal de métricas
+// @Description Retorna, para cada transportadora, a quantidade de cotações e os preços médio, mínimo e máximo por hora, dia ou semana
+// @Tags métricas
+// @Accept json
+// @Produce json
+// @Param bucket query string false "Granularidade dos intervalos (padrão: day)" Enums(hour, day, week)
+// @Param from query string false "Início do período (RFC 3339 ou AAAA-MM-DD no fuso informado)"
+// @Param to query string false "Fim do período, exclusivo (RFC 3339 ou AAAA-MM-DD, que inclui o dia inteiro)"
+// @Param period query string false "Período relativo, alternativo a from/to" Enums(last_24h, last_7d, last_30d, today, this_month)
+// @Param timezone query string false "Fuso horário IANA usado nos intervalos e datas (padrão: UTC)"
+// @Success 200 {object} domain.MetricsSeries "Série temporal de métricas"
+// @Failure 400 {object} ProblemDetails "Erro de parâmetro inválido"
+// @Failure 422 {object} ProblemDetails "Erros de validação por campo"
+// @Failure 500 {object} ProblemDetails "Erro interno do servidor"
+// @Router /metrics/timeseries [get]
+func (c *MetricsController) GetMetricsSeries(ctx *gin.Context) {
+	metricsQuery, err := parseMetricsQuery(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
 
-		// Log the last_quotes parameter for debugging
-		fmt.Printf("Processing request with last_quotes=%d\n", lastQuotes)
-	} else {
-		fmt.Println("Processing request without last_quotes parameter")
+	query := domain.MetricsSeriesQuery{
+		MetricsQuery: metricsQuery,
+		Bucket:       domain.MetricsBucket(ctx.DefaultQuery("bucket", string(domain.BucketDay))),
 	}
 
-	// Execute use case
-	metrics, err := c.getMetricsUseCase.Execute(ctx, lastQuotes)
+	if err := query.Validate(); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	series, err := c.getMetricsSeriesUseCase.Execute(ctx, query)
 	if err != nil {
-		fmt.Printf("Error getting metrics: %v\n", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get metrics: " + err.Error()})
+		respondError(ctx, err)
 		return
 	}
 
-	// Log detailed metrics for debugging
-	fmt.Printf("Returning metrics with %d carrier metrics\n", len(metrics.CarrierMetrics))
+	ctx.JSON(http.StatusOK, series)
+}
 
-	// Use a wait group for concurrent logging
-	var logWg sync.WaitGroup
+// parseMetricsQuery lê a janela das métricas da query string
+func parseMetricsQuery(ctx *gin.Context) (domain.MetricsQuery, error) {
+	query := domain.MetricsQuery{
+		Period:   domain.MetricsPeriod(ctx.Query("period")),
+		Location: time.UTC,
+	}
 
-	// Log metrics concurrently
-	for i, metric := range metrics.CarrierMetrics {
-		logWg.Add(1)
-		go func(index int, m domain.QuoteMetrics) {
-			defer logWg.Done()
-			fmt.Printf("  [%d] Carrier: %s, TotalQuotes: %d, TotalPrice: %.2f, AvgPrice: %.2f\n",
-				index, m.CarrierName, m.TotalQuotes, m.TotalShippingPrice, m.AverageShippingPrice)
-		}(i, metric)
+	// group_by combines an optional region with an optional row key, e.g. "state,carrier_service"
+	for _, dimension := range splitList(ctx.Query("group_by")) {
+		switch key := domain.MetricsKey(dimension); key {
+		case domain.KeyCarrier, domain.KeyCarrierService, domain.KeyModal:
+			if query.Key != "" {
+				return query, domain.NewInvalidRequestError("group_by accepts only one of carrier, carrier_service, modal", nil)
+			}
+			query.Key = key
+		default:
+			if query.Region != "" {
+				return query, domain.NewInvalidRequestError("group_by accepts only one of state, cep_prefix", nil)
+			}
+			query.Region = domain.MetricsRegion(dimension)
+		}
 	}
 
-	// Wait for all logging to complete
-	logWg.Wait()
+	if query.Region == domain.RegionCepPrefix {
+		query.CepPrefixLength = domain.DefaultCepPrefixLength
+	}
+	if cepPrefixLength := ctx.Query("cep_prefix_length"); cepPrefixLength != "" {
+		value, err := strconv.Atoi(cepPrefixLength)
+		if err != nil {
+			return query, domain.NewInvalidRequestError("cep_prefix_length must be a valid integer", err)
+		}
+		query.CepPrefixLength = value
+	}
 
-	fmt.Printf("Cheapest: %.2f, Most Expensive: %.2f\n",
-		metrics.CheapestAndMostExpensive.CheapestShipping,
-		metrics.CheapestAndMostExpensive.MostExpensiveShipping)
+	if timezone := ctx.Query("timezone"); timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err == nil && loc == time.Local {
+			err = errors.New("the server local time zone is not accepted")
+		}
+		if err != nil {
+			return query, domain.NewInvalidRequestError("timezone must be a valid IANA time zone name", err)
+		}
+		query.Location = loc
+	}
+
+	if percentiles := ctx.Query("percentiles"); percentiles != "" {
+		value, err := strconv.ParseBool(percentiles)
+		if err != nil {
+			return query, domain.NewInvalidRequestError("percentiles must be true or false", err)
+		}
+		query.SkipPercentiles = !value
+	}
+
+	if lastQuotes := ctx.Query("last_quotes"); lastQuotes != "" {
+		value, err := strconv.Atoi(lastQuotes)
+		if err != nil {
+			return query, domain.NewInvalidRequestError("last_quotes must be a valid integer", err)
+		}
+		query.LastQuotes = value
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		value, _, err := parseQueryTime(from, query.Location)
+		if err != nil {
+			return query, domain.NewInvalidRequestError("from must be an RFC 3339 timestamp or a YYYY-MM-DD date", err)
+		}
+		query.From = &value
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		value, dateOnly, err := parseQueryTime(to, query.Location)
+		if err != nil {
+			return query, domain.NewInvalidRequestError("to must be an RFC 3339 timestamp or a YYYY-MM-DD date", err)
+		}
+		if dateOnly {
+			// A date includes the whole day
+			value = value.AddDate(0, 0, 1)
+		}
+		query.To = &value
+	}
 
-	// Return response
-	ctx.JSON(http.StatusOK, metrics)
+	return query, nil
 }
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+func TestParseMetricsQuery(t *testing.T) {
+	query, err := parseMetricsQuery(newQueryContext("from=2025-01-01&to=2025-01-31&timezone=America/Sao_Paulo&last_quotes=10"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "America/Sao_Paulo", query.Location.String())
+	// Dates are midnight in the requested time zone
+	assert.True(t, time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC).Equal(*query.From))
+	assert.True(t, time.Date(2025, 2, 1, 3, 0, 0, 0, time.UTC).Equal(*query.To))
+	assert.Equal(t, 10, query.LastQuotes)
+}
+
+func TestParseMetricsQueryDefaults(t *testing.T) {
+	query, err := parseMetricsQuery(newQueryContext(""))
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.MetricsQuery{Location: time.UTC}, query)
+}
+
+func TestParseMetricsQueryPercentiles(t *testing.T) {
+	query, err := parseMetricsQuery(newQueryContext("percentiles=false"))
+	assert.NoError(t, err)
+	assert.True(t, query.SkipPercentiles)
+
+	query, err = parseMetricsQuery(newQueryContext("percentiles=true"))
+	assert.NoError(t, err)
+	assert.False(t, query.SkipPercentiles)
+}
+
+func TestParseMetricsQueryInvalid(t *testing.T) {
+	for _, query := range []string{"last_quotes=ten", "timezone=Mars/Olympus", "timezone=Local", "from=yesterday", "to=2025-13-01", "group_by=cep_prefix&cep_prefix_length=two", "group_by=carrier,modal", "group_by=state,cep_prefix", "percentiles=maybe"} {
+		_, err := parseMetricsQuery(newQueryContext(query))
+		assert.True(t, errors.Is(err, domain.ErrInvalidRequest), query)
+	}
+}
+
+func TestParseMetricsQueryGroupBy(t *testing.T) {
+	query, err := parseMetricsQuery(newQueryContext("group_by=cep_prefix"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RegionCepPrefix, query.Region)
+	assert.Equal(t, domain.DefaultCepPrefixLength, query.CepPrefixLength)
+
+	query, err = parseMetricsQuery(newQueryContext("group_by=cep_prefix&cep_prefix_length=5"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, query.CepPrefixLength)
+
+	query, err = parseMetricsQuery(newQueryContext("group_by=state"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RegionState, query.Region)
+	assert.Zero(t, query.CepPrefixLength)
+}
+
+func TestParseMetricsQueryGroupByKey(t *testing.T) {
+	query, err := parseMetricsQuery(newQueryContext("group_by=carrier_service"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.KeyCarrierService, query.Key)
+	assert.Empty(t, query.Region)
+
+	query, err = parseMetricsQuery(newQueryContext("group_by=state,%20modal"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RegionState, query.Region)
+	assert.Equal(t, domain.KeyModal, query.Key)
+}
+
+func TestRespondWithETag(t *testing.T) {
+	body := []byte(`{"carrier_metrics":[]}`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	respondWithETag(ctx, "application/json; charset=utf-8", body)
+
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, string(body), w.Body.String())
+
+	// The same content revalidated with its ETag is not sent again
+	w = httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	ctx.Request.Header.Set("If-None-Match", `"stale", `+etag)
+	respondWithETag(ctx, "application/json; charset=utf-8", body)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestETagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`W/"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`"xyz" , "abc"`, `"abc"`))
+	assert.True(t, etagMatches(`*`, `"abc"`))
+	assert.False(t, etagMatches(``, `"abc"`))
+	assert.False(t, etagMatches(`"abcd"`, `"abc"`))
+}
+
+func TestRenderMetrics(t *testing.T) {
+	median := 16.0
+	metrics := &domain.MetricsResponse{
+		CarrierMetrics: []domain.QuoteMetrics{
+			{CarrierName: "EXPRESSO FR", TotalQuotes: 2, TotalShippingPrice: 35.0, AverageShippingPrice: 17.5, MinShippingPrice: 15.0,
+				MaxShippingPrice: 20.0, MedianShippingPrice: &median, CheapestCount: 1, CheapestShare: 0.5},
+		},
+		Regions: []domain.RegionMetrics{
+			{Region: "SP", CarrierMetrics: []domain.QuoteMetrics{{CarrierName: "EXPRESSO FR", TotalQuotes: 1}}},
+		},
+	}
+
+	body, err := renderMetrics(metrics, contentTypeCSV)
+	assert.NoError(t, err)
+	assert.Equal(t, "region,carrier_name,service,modal,total_quotes,total_shipping_price,average_shipping_price,min_shipping_price,"+
+		"max_shipping_price,median_shipping_price,p90_shipping_price,p95_shipping_price,stddev_shipping_price,average_delivery_days,"+
+		"min_delivery_days,max_delivery_days,cheapest_count,cheapest_share\n"+
+		",EXPRESSO FR,,,2,35,17.5,15,20,16,,,0,0,0,0,1,0.5\n"+
+		"SP,EXPRESSO FR,,,1,0,0,0,0,,,,0,0,0,0,0,0\n", string(body))
+
+	// NDJSON writes the same rows, one object per line
+	body, err = renderMetrics(metrics, contentTypeNDJSON)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"median_shipping_price":16`)
+	assert.NotContains(t, lines[0], `"region"`)
+	assert.Contains(t, lines[1], `"region":"SP"`)
+
+	body, err = renderMetrics(metrics, contentTypeJSON)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"carrier_metrics"`)
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	for accept, expected := range map[string]string{
+		"":                                  contentTypeJSON,
+		"*/*":                               contentTypeJSON,
+		"text/csv":                          contentTypeCSV,
+		"text/*":                            contentTypeCSV,
+		"application/x-ndjson, text/csv":    contentTypeNDJSON,
+		"application/json, text/plain, */*": contentTypeJSON,
+	} {
+		ctx := newQueryContext("")
+		ctx.Request.Header.Set("Accept", accept)
+		format, err := negotiateFormat(ctx, contentTypeJSON, contentTypeCSV, contentTypeNDJSON)
+		assert.NoError(t, err, accept)
+		assert.Equal(t, expected, format, accept)
+	}
+
+	ctx := newQueryContext("")
+	ctx.Request.Header.Set("Accept", "application/xml")
+	_, err := negotiateFormat(ctx, contentTypeJSON, contentTypeCSV)
+	assert.True(t, errors.Is(err, domain.ErrNotAcceptable))
+}
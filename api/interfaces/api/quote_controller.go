@@ -2,6 +2,8 @@ package api
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
@@ -25,49 +27,114 @@ func NewQuoteController(getShippingQuotationUseCase *usecases.GetShippingQuotati
 // @Accept json
 // @Produce json
 // @Param request body domain.QuoteRequest true "Dados para cotação de frete"
+// @Param detail query string false "Use 'full' para incluir os detalhes completos de cada oferta" Enums(summary, full)
+// @Param sort query string false "Ordena as ofertas por preço ou prazo" Enums(price, deadline)
+// @Param max_price query number false "Preço máximo aceito"
+// @Param max_days query int false "Prazo máximo aceito, em dias"
+// @Param carriers query string false "Transportadoras permitidas, separadas por vírgula"
+// @Param exclude_carriers query string false "Transportadoras bloqueadas, separadas por vírgula"
+// @Param modal query string false "Modal aceito, ex.: Rodoviário"
 // @Success 200 {object} domain.QuoteResponse "Cotações de frete disponíveis"
-// @Failure 400 {object} map[string]string "Erro de requisição inválida"
-// @Failure 500 {object} map[string]string "Erro interno do servidor"
+// @Header 200 {string} X-Cache "HIT quando a cotação foi servida do cache, MISS caso contrário"
+// @Failure 400 {object} ProblemDetails "Erro de requisição inválida"
+// @Failure 422 {object} ProblemDetails "Erros de validação por campo"
+// @Failure 500 {object} ProblemDetails "Erro interno do servidor"
+// @Failure 502 {object} ProblemDetails "Cotação rejeitada pela transportadora"
+// @Failure 503 {object} ProblemDetails "Transportadoras temporariamente indisponíveis"
+// @Failure 504 {object} ProblemDetails "Tempo limite da requisição excedido"
+// @Header 503 {integer} Retry-After "Segundos até uma nova tentativa"
 // @Router /quote [post]
 func (c *QuoteController) GetQuote(ctx *gin.Context) {
 	var request domain.QuoteRequest
 
+	detail := ctx.DefaultQuery("detail", "summary")
+	if detail != "summary" && detail != "full" {
+		respondError(ctx, domain.NewInvalidRequestError("detail must be 'summary' or 'full'", nil))
+		return
+	}
+
+	options, err := parseQuoteOptions(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	if err := options.Validate(); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		respondError(ctx, domain.NewInvalidRequestError("Invalid request format", err))
 		return
 	}
 
-	if err := validateQuoteRequest(request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := request.Validate(); err != nil {
+		respondError(ctx, err)
 		return
 	}
 
-	response, err := c.getShippingQuotationUseCase.Execute(ctx, request)
+	// gin.Context is pooled and, without ContextWithFallback, never reports cancellation,
+	// so the use case gets the request context to stop waiting when the client goes away
+	response, err := c.getShippingQuotationUseCase.Execute(ctx.Request.Context(), request)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shipping quotation: " + err.Error()})
+		respondError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
-}
+	if response.CacheHit {
+		ctx.Header("X-Cache", "HIT")
+	} else {
+		ctx.Header("X-Cache", "MISS")
+	}
+
+	result := response.ApplyOptions(options)
 
-func validateQuoteRequest(request domain.QuoteRequest) error {
-	if request.Recipient.Address.Zipcode == "" {
-		return &InputError{Field: "recipient.address.zipcode", Message: "Zipcode cannot be empty"}
+	if detail != "full" {
+		result = result.WithoutDetails()
 	}
 
-	if len(request.Volumes) == 0 {
-		return &InputError{Field: "volumes", Message: "At least one volume is required"}
+	if len(request.Dispatchers) > 0 {
+		result = result.GroupByDispatcher()
 	}
 
-	return nil
+	ctx.JSON(http.StatusOK, result)
 }
 
-type InputError struct {
-	Field   string
-	Message string
+// parseQuoteOptions lê as opções de ordenação e filtragem da query string
+func parseQuoteOptions(ctx *gin.Context) (domain.QuoteOptions, error) {
+	options := domain.QuoteOptions{
+		Sort:            domain.QuoteSort(ctx.Query("sort")),
+		Carriers:        splitList(ctx.Query("carriers")),
+		ExcludeCarriers: splitList(ctx.Query("exclude_carriers")),
+		Modal:           strings.TrimSpace(ctx.Query("modal")),
+	}
+
+	if maxPrice := ctx.Query("max_price"); maxPrice != "" {
+		value, err := strconv.ParseFloat(maxPrice, 64)
+		if err != nil {
+			return options, domain.NewInvalidRequestError("max_price must be a valid number", err)
+		}
+		options.MaxPrice = value
+	}
+
+	if maxDays := ctx.Query("max_days"); maxDays != "" {
+		value, err := strconv.Atoi(maxDays)
+		if err != nil {
+			return options, domain.NewInvalidRequestError("max_days must be a valid integer", err)
+		}
+		options.MaxDays = value
+	}
+
+	return options, nil
 }
 
-func (e *InputError) Error() string {
-	return e.Message + " for field " + e.Field
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+)
+
+// exportFlushRows is how many exported rows are buffered before they are sent to the client
+const exportFlushRows = 500
+
+type QuoteHistoryController struct {
+	listQuotesUseCase   *usecases.ListQuotesUseCase
+	getQuoteUseCase     *usecases.GetQuoteUseCase
+	exportQuotesUseCase *usecases.ExportQuotesUseCase
+}
+
+func NewQuoteHistoryController(
+	listQuotesUseCase *usecases.ListQuotesUseCase,
+	getQuoteUseCase *usecases.GetQuoteUseCase,
+	exportQuotesUseCase *usecases.ExportQuotesUseCase,
+) *QuoteHistoryController {
+	return &QuoteHistoryController{
+		listQuotesUseCase:   listQuotesUseCase,
+		getQuoteUseCase:     getQuoteUseCase,
+		exportQuotesUseCase: exportQuotesUseCase,
+	}
+}
+
+// ListQuotes lista as cotações armazenadas
+// @Summary Listar cotações
+// @Description Retorna as cotações armazenadas, da mais recente para a mais antiga, paginadas por cursor
+// @Tags cotações
+// @Produce json
+// @Param from query string false "Início do período (RFC 3339 ou AAAA-MM-DD, inclusivo)"
+// @Param to query string false "Fim do período (RFC 3339 exclusivo, ou AAAA-MM-DD inclusivo)"
+// @Param carrier query string false "Nome de uma transportadora presente nas ofertas"
+// @Param zipcode query string false "CEP do destinatário"
+// @Param cursor query string false "Cursor retornado em next_cursor pela página anterior"
+// @Param limit query int false "Quantidade de cotações por página (1 a 100, padrão 20)"
+// @Success 200 {object} domain.QuotePage "Página de cotações"
+// @Failure 400 {object} ProblemDetails "Erro de parâmetro inválido"
+// @Failure 422 {object} ProblemDetails "Erros de validação por parâmetro"
+// @Failure 500 {object} ProblemDetails "Erro interno do servidor"
+// @Router /quotes [get]
+func (c *QuoteHistoryController) ListQuotes(ctx *gin.Context) {
+	filter, err := parseQuoteFilter(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	page, err := c.listQuotesUseCase.Execute(ctx.Request.Context(), filter)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// GetQuote retorna uma cotação armazenada
+// @Summary Obter cotação
+// @Description Retorna uma cotação armazenada pelo ID, com os detalhes completos das ofertas
+// @Tags cotações
+// @Produce json
+// @Param id path int true "ID da cotação"
+// @Success 200 {object} domain.QuoteResponse "Cotação armazenada"
+// @Failure 400 {object} ProblemDetails "ID inválido"
+// @Failure 404 {object} ProblemDetails "Cotação não encontrada"
+// @Failure 500 {object} ProblemDetails "Erro interno do servidor"
+// @Router /quotes/{id} [get]
+func (c *QuoteHistoryController) GetQuote(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		respondError(ctx, domain.NewInvalidRequestError("id must be a positive integer", err))
+		return
+	}
+
+	quote, err := c.getQuoteUseCase.Execute(ctx.Request.Context(), uint(id))
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
+// ExportQuotes exporta as ofertas das cotações armazenadas
+// @Summary Exportar cotações
+// @Description Exporta uma linha por oferta de transportadora das cotações armazenadas, da mais antiga para a mais recente, em CSV (padrão) ou NDJSON conforme o cabeçalho Accept. A resposta é enviada em fluxo, sem limite de cotações.
+// @Tags cotações
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param from query string false "Início do período (RFC 3339 ou AAAA-MM-DD, inclusivo)"
+// @Param to query string false "Fim do período (RFC 3339 exclusivo, ou AAAA-MM-DD inclusivo)"
+// @Param carrier query string false "Exporta apenas as ofertas desta transportadora"
+// @Param zipcode query string false "CEP do destinatário"
+// @Success 200 {array} domain.QuoteExportRow "Uma oferta por linha"
+// @Failure 400 {object} ProblemDetails "Erro de parâmetro inválido"
+// @Failure 406 {object} ProblemDetails "Nenhum formato aceito pelo cabeçalho Accept"
+// @Failure 422 {object} ProblemDetails "Erros de validação por parâmetro"
+// @Failure 500 {object} ProblemDetails "Erro interno do servidor"
+// @Router /quotes/export [get]
+func (c *QuoteHistoryController) ExportQuotes(ctx *gin.Context) {
+	format, err := negotiateFormat(ctx, contentTypeCSV, contentTypeNDJSON)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	filter, err := parseQuoteExportFilter(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	if err := filter.Validate(); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	writer, err := newRowWriter(ctx.Writer, format, quoteExportCSVHeader)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	filename := "quotes.csv"
+	if format == contentTypeNDJSON {
+		filename = "quotes.ndjson"
+	}
+	ctx.Header("Content-Type", format+"; charset=utf-8")
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	// The request context stops the database cursor when the client goes away
+	written := 0
+	err = c.exportQuotesUseCase.Execute(ctx.Request.Context(), filter, func(row domain.QuoteExportRow) error {
+		if err := writer.write(row, quoteExportCSVRecord(row)); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushRows == 0 {
+			if err := writer.flush(); err != nil {
+				return err
+			}
+			ctx.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.flush()
+	}
+	if err != nil {
+		if !ctx.Writer.Written() {
+			ctx.Writer.Header().Del("Content-Disposition")
+			respondError(ctx, err)
+			return
+		}
+		// The status line is already sent, so the truncated body is the only signal left to the client
+		log.Printf("ERROR: %s %s: export interrupted after %d rows: %v", ctx.Request.Method, ctx.Request.URL.Path, written, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// parseQuoteFilter lê os filtros e a paginação do histórico da query string
+func parseQuoteFilter(ctx *gin.Context) (domain.QuoteFilter, error) {
+	filter := domain.QuoteFilter{
+		CarrierName:      ctx.Query("carrier"),
+		RecipientZipcode: ctx.Query("zipcode"),
+		Limit:            domain.DefaultQuotePageSize,
+	}
+
+	var err error
+	if filter.From, filter.To, err = parseQuotePeriod(ctx); err != nil {
+		return filter, err
+	}
+
+	if cursor := ctx.Query("cursor"); cursor != "" {
+		value, err := domain.DecodeQuoteCursor(cursor)
+		if err != nil {
+			return filter, domain.NewInvalidRequestError("cursor is invalid", err)
+		}
+		filter.Cursor = value
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			return filter, domain.NewInvalidRequestError("limit must be a valid integer", err)
+		}
+		filter.Limit = value
+	}
+
+	return filter, nil
+}
+
+// parseQuoteExportFilter lê os filtros da exportação da query string
+func parseQuoteExportFilter(ctx *gin.Context) (domain.QuoteExportFilter, error) {
+	filter := domain.QuoteExportFilter{
+		CarrierName:      ctx.Query("carrier"),
+		RecipientZipcode: ctx.Query("zipcode"),
+	}
+
+	var err error
+	filter.From, filter.To, err = parseQuotePeriod(ctx)
+	return filter, err
+}
+
+// parseQuotePeriod lê o período from/to do histórico, em UTC
+func parseQuotePeriod(ctx *gin.Context) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if value := ctx.Query("from"); value != "" {
+		parsed, _, err := parseQueryTime(value, time.UTC)
+		if err != nil {
+			return nil, nil, domain.NewInvalidRequestError("from must be an RFC 3339 timestamp or a YYYY-MM-DD date", err)
+		}
+		from = &parsed
+	}
+
+	if value := ctx.Query("to"); value != "" {
+		parsed, dateOnly, err := parseQueryTime(value, time.UTC)
+		if err != nil {
+			return nil, nil, domain.NewInvalidRequestError("to must be an RFC 3339 timestamp or a YYYY-MM-DD date", err)
+		}
+		if dateOnly {
+			// A date includes the whole day
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = &parsed
+	}
+
+	return from, to, nil
+}
+
+// parseQueryTime aceita um timestamp RFC 3339 ou uma data AAAA-MM-DD, interpretada no fuso informado
+func parseQueryTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if date, err := time.ParseInLocation(time.DateOnly, value, loc); err == nil {
+		return date, true, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	return timestamp, false, err
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/domain/mocks"
+	"gorm.io/gorm"
+)
+
+func newQueryContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/quotes?"+query, nil)
+	return ctx
+}
+
+func TestParseQuoteFilter(t *testing.T) {
+	cursor := domain.QuoteCursor{CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), ID: 42}
+
+	filter, err := parseQuoteFilter(newQueryContext("from=2025-01-01&to=2025-01-31&carrier=Correios&zipcode=01311000&limit=50&cursor=" + cursor.Encode()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	// A date-only upper bound includes the whole day
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), *filter.To)
+	assert.Equal(t, "Correios", filter.CarrierName)
+	assert.Equal(t, "01311000", filter.RecipientZipcode)
+	assert.Equal(t, 50, filter.Limit)
+	assert.Equal(t, cursor.ID, filter.Cursor.ID)
+	assert.True(t, cursor.CreatedAt.Equal(filter.Cursor.CreatedAt))
+}
+
+func TestParseQuoteFilterDefaults(t *testing.T) {
+	filter, err := parseQuoteFilter(newQueryContext(""))
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.QuoteFilter{Limit: domain.DefaultQuotePageSize}, filter)
+}
+
+func TestParseQuoteFilterInvalid(t *testing.T) {
+	for _, query := range []string{"from=yesterday", "to=2025-13-01", "cursor=not-a-cursor", "limit=ten"} {
+		_, err := parseQuoteFilter(newQueryContext(query))
+		assert.True(t, errors.Is(err, domain.ErrInvalidRequest), query)
+	}
+}
+
+func performExportQuotes(t *testing.T, repo *mocks.MockQuoteRepository, accept, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	controller := NewQuoteHistoryController(nil, nil, usecases.NewExportQuotesUseCase(repo))
+
+	// Registered next to /quotes/:id, as in the router
+	router := gin.New()
+	router.GET("/quotes/:id", controller.GetQuote)
+	router.GET("/quotes/export", controller.ExportQuotes)
+
+	w := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/quotes/export?"+query, nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	router.ServeHTTP(w, request)
+	return w
+}
+
+func newExportedQuotes() []domain.QuoteResponse {
+	createdAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	return []domain.QuoteResponse{
+		{
+			Model: gorm.Model{ID: 42, CreatedAt: createdAt},
+			Carriers: []domain.Carrier{
+				{Name: "EXPRESSO FR", Service: "Rodoviário", Price: 17.0, DeadlineDays: 3},
+				{Name: "Correios", Service: "SEDEX, 10", Price: 25.5, DeadlineDays: 1},
+			},
+			RecipientZipcode: "01311000",
+		},
+	}
+}
+
+func TestExportQuotesCSV(t *testing.T) {
+	repo := new(mocks.MockQuoteRepository)
+	repo.On("ExportQuotes", mock.Anything, domain.QuoteExportFilter{RecipientZipcode: "01311000"}).Return(newExportedQuotes(), nil)
+
+	w := performExportQuotes(t, repo, "", "zipcode=01311000")
+
+	// CSV is the default, one line per offer after the header
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="quotes.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "quote_id,created_at,recipient_zipcode,carrier,service,modal,price,deadline_days,estimated_delivery_date,cheapest,fastest\n"+
+		"42,2025-01-10T12:00:00Z,01311000,EXPRESSO FR,Rodoviário,,17,3,,true,false\n"+
+		"42,2025-01-10T12:00:00Z,01311000,Correios,\"SEDEX, 10\",,25.5,1,,false,true\n", w.Body.String())
+}
+
+func TestExportQuotesNDJSON(t *testing.T) {
+	repo := new(mocks.MockQuoteRepository)
+	repo.On("ExportQuotes", mock.Anything, domain.QuoteExportFilter{CarrierName: "correios"}).Return(newExportedQuotes(), nil)
+
+	w := performExportQuotes(t, repo, "application/x-ndjson", "carrier=correios")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"quote_id":42,"created_at":"2025-01-10T12:00:00Z","recipient_zipcode":"01311000","carrier":"Correios",`+
+		`"service":"SEDEX, 10","modal":"","price":25.5,"deadline_days":1,"cheapest":false,"fastest":true}`+"\n", w.Body.String())
+}
+
+func TestExportQuotesErrors(t *testing.T) {
+	repo := new(mocks.MockQuoteRepository)
+
+	w := performExportQuotes(t, repo, "application/json", "")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+
+	w = performExportQuotes(t, repo, "", "zipcode=123")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// A failure before any row is written is still reported as a problem
+	repo.On("ExportQuotes", mock.Anything, domain.QuoteExportFilter{}).Return(nil, domain.NewPersistenceError(errors.New("db down")))
+	w = performExportQuotes(t, repo, "text/csv", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/circuitbreaker"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/interfaces/api"
 )
@@ -12,12 +13,19 @@ import (
 func SetupRouter(
 	getShippingQuotationUseCase *usecases.GetShippingQuotationUseCase,
 	getMetricsUseCase *usecases.GetMetricsUseCase,
+	getMetricsSeriesUseCase *usecases.GetMetricsSeriesUseCase,
+	listQuotesUseCase *usecases.ListQuotesUseCase,
+	getQuoteUseCase *usecases.GetQuoteUseCase,
+	exportQuotesUseCase *usecases.ExportQuotesUseCase,
+	circuitBreaker *circuitbreaker.CircuitBreaker,
 ) *gin.Engine {
 	router := gin.Default()
 
 	// Create controllers
 	quoteController := api.NewQuoteController(getShippingQuotationUseCase)
-	metricsController := api.NewMetricsController(getMetricsUseCase)
+	quoteHistoryController := api.NewQuoteHistoryController(listQuotesUseCase, getQuoteUseCase, exportQuotesUseCase)
+	metricsController := api.NewMetricsController(getMetricsUseCase, getMetricsSeriesUseCase)
+	diagnosticsController := api.NewDiagnosticsController(circuitBreaker)
 
 	// Swagger documentation route
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -28,8 +36,17 @@ func SetupRouter(
 		// Quote route
 		apiGroup.POST("/quote", quoteController.GetQuote)
 
-		// Metrics route
+		// Quote history routes
+		apiGroup.GET("/quotes", quoteHistoryController.ListQuotes)
+		apiGroup.GET("/quotes/export", quoteHistoryController.ExportQuotes)
+		apiGroup.GET("/quotes/:id", quoteHistoryController.GetQuote)
+
+		// Metrics routes
 		apiGroup.GET("/metrics", metricsController.GetMetrics)
+		apiGroup.GET("/metrics/timeseries", metricsController.GetMetricsSeries)
+
+		// Diagnostics route
+		apiGroup.GET("/diagnostics/circuit-breakers", diagnosticsController.GetCircuitBreakers)
 	}
 
 	return router
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/database"
 	"gorm.io/gorm"
 )
 
@@ -52,10 +53,10 @@ func TestMetricsEndpoint_Integration(t *testing.T) {
 	}
 
 	// Save quotes to database
-	err := testQuoteRepository.SaveQuote(context.Background(), quote1)
+	err := testQuoteRepository.SaveQuote(context.Background(), quote1, domain.QuoteRequest{})
 	assert.NoError(t, err)
 
-	err = testQuoteRepository.SaveQuote(context.Background(), quote2)
+	err = testQuoteRepository.SaveQuote(context.Background(), quote2, domain.QuoteRequest{})
 	assert.NoError(t, err)
 
 	// Create HTTP request
@@ -94,3 +95,93 @@ func TestMetricsEndpoint_Integration(t *testing.T) {
 		}
 	}
 }
+
+func TestMetricsAggregates_MatchWindowedMetrics_Integration(t *testing.T) {
+	// Skip if test environment is not set up
+	if testDB == nil {
+		t.Skip("Test environment not set up")
+	}
+
+	assert.NoError(t, cleanupDB(testDB))
+	defer cleanupDB(testDB)
+
+	// Ties on the cheapest price and several offers of one carrier in a quote are the cases
+	// where running totals can drift from a scan of quote_offers
+	now := time.Now()
+	quotes := []*domain.QuoteResponse{
+		{
+			Model: gorm.Model{CreatedAt: now.Add(-2 * time.Hour)},
+			Carriers: []domain.Carrier{
+				{Name: "EXPRESSO FR", Service: "Rodoviário", DeadlineDays: 3, Price: 17.0},
+				{Name: "EXPRESSO FR", Service: "Expresso", DeadlineDays: 1, Price: 29.5},
+				{Name: "Correios", Service: "PAC", DeadlineDays: 6, Price: 17.0},
+			},
+		},
+		{
+			Model: gorm.Model{CreatedAt: now.Add(-1 * time.Hour)},
+			Carriers: []domain.Carrier{
+				{Name: "Correios", Service: "SEDEX", DeadlineDays: 1, Price: 25.0},
+				{Name: "BTU", Service: "Rodoviário", DeadlineDays: 4, Price: 21.3},
+			},
+		},
+		{
+			Model: gorm.Model{CreatedAt: now},
+			Carriers: []domain.Carrier{
+				{Name: "EXPRESSO FR", Service: "Rodoviário", DeadlineDays: 2, Price: 14.9},
+				{Name: "BTU", Service: "Rodoviário", DeadlineDays: 5, Price: 19.8},
+			},
+		},
+	}
+	for _, quote := range quotes {
+		assert.NoError(t, testQuoteRepository.SaveQuote(context.Background(), quote, domain.QuoteRequest{}))
+	}
+
+	// A window covering every quote is computed from quote_offers
+	from := now.Add(-24 * time.Hour)
+	windowed, err := testMetricsRepository.GetMetrics(context.Background(), domain.MetricsQuery{From: &from})
+	assert.NoError(t, err)
+	assert.Len(t, windowed.CarrierMetrics, 3)
+
+	assertMatchesWindowed := func(t *testing.T) {
+		aggregated, err := testMetricsRepository.GetMetrics(context.Background(), domain.MetricsQuery{SkipPercentiles: true})
+		assert.NoError(t, err)
+		if !assert.Len(t, aggregated.CarrierMetrics, len(windowed.CarrierMetrics)) {
+			return
+		}
+
+		assert.Equal(t, windowed.CheapestAndMostExpensive, aggregated.CheapestAndMostExpensive)
+		for i, expected := range windowed.CarrierMetrics {
+			actual := aggregated.CarrierMetrics[i]
+			assert.Equal(t, expected.CarrierName, actual.CarrierName)
+			assert.Equal(t, expected.TotalQuotes, actual.TotalQuotes, expected.CarrierName)
+			assert.InDelta(t, expected.TotalShippingPrice, actual.TotalShippingPrice, 1e-9, expected.CarrierName)
+			assert.InDelta(t, expected.AverageShippingPrice, actual.AverageShippingPrice, 1e-9, expected.CarrierName)
+			assert.Equal(t, expected.MinShippingPrice, actual.MinShippingPrice, expected.CarrierName)
+			assert.Equal(t, expected.MaxShippingPrice, actual.MaxShippingPrice, expected.CarrierName)
+			assert.InDelta(t, expected.StddevShippingPrice, actual.StddevShippingPrice, 1e-9, expected.CarrierName)
+			assert.InDelta(t, expected.AverageDeliveryDays, actual.AverageDeliveryDays, 1e-9, expected.CarrierName)
+			assert.Equal(t, expected.MinDeliveryDays, actual.MinDeliveryDays, expected.CarrierName)
+			assert.Equal(t, expected.MaxDeliveryDays, actual.MaxDeliveryDays, expected.CarrierName)
+			assert.Equal(t, expected.CheapestCount, actual.CheapestCount, expected.CarrierName)
+			assert.InDelta(t, expected.CheapestShare, actual.CheapestShare, 1e-9, expected.CarrierName)
+
+			// Percentiles cannot be accumulated, so only the windowed metrics carry them
+			assert.NotNil(t, expected.MedianShippingPrice, expected.CarrierName)
+			assert.Nil(t, actual.MedianShippingPrice, expected.CarrierName)
+		}
+	}
+
+	t.Run("default keeps percentiles", func(t *testing.T) {
+		unbounded, err := testMetricsRepository.GetMetrics(context.Background(), domain.MetricsQuery{})
+		assert.NoError(t, err)
+		assert.Equal(t, windowed, unbounded)
+	})
+
+	t.Run("maintained by SaveQuote", assertMatchesWindowed)
+
+	t.Run("after rebuild", func(t *testing.T) {
+		assert.NoError(t, testDB.Exec("UPDATE carrier_aggregates SET offer_count = 0, cheapest_count = 0").Error)
+		assert.NoError(t, database.RebuildCarrierAggregates(context.Background(), testDB))
+		assertMatchesWindowed(t)
+	})
+}
@@ -6,9 +6,12 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/application/circuitbreaker"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/application/usecases"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/config"
 	domain "github.com/thalesmacedo1/freterapido-backend-api/api/domain/entities"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/database"
+	"github.com/thalesmacedo1/freterapido-backend-api/api/infrastructure/freterapido"
 	"github.com/thalesmacedo1/freterapido-backend-api/api/interfaces/routers"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -35,7 +38,7 @@ func setupTestDB() (*gorm.DB, error) {
 	}
 
 	// Migrate the schema
-	if err := db.AutoMigrate(&domain.QuoteResponse{}); err != nil {
+	if err := database.RunMigrations(db); err != nil {
 		return nil, err
 	}
 
@@ -44,7 +47,7 @@ func setupTestDB() (*gorm.DB, error) {
 
 // cleanupDB clears all test data
 func cleanupDB(db *gorm.DB) error {
-	return db.Exec("TRUNCATE TABLE quote_responses CASCADE").Error
+	return db.Exec("TRUNCATE TABLE quote_responses, carrier_aggregates CASCADE").Error
 }
 
 // setupTestEnvironment initializes the test environment
@@ -63,12 +66,29 @@ func setupTestEnvironment() error {
 	testQuoteRepository = database.NewQuoteRepository(testDB)
 	testMetricsRepository = database.NewMetricsRepository(testDB)
 
+	// Initialize shipping provider
+	freteRapidoConfig, err := config.LoadFreteRapidoConfig()
+	if err != nil {
+		return err
+	}
+	shippingProvider := freterapido.NewClient(freteRapidoConfig, nil)
+
+	circuitBreakerConfig, err := config.LoadCircuitBreakerConfig()
+	if err != nil {
+		return err
+	}
+	freteRapidoBreaker := circuitbreaker.NewCircuitBreaker("frete_rapido", circuitBreakerConfig)
+
 	// Initialize use cases
-	getShippingQuotationUseCase := usecases.NewGetShippingQuotationUseCase(testQuoteRepository)
-	getMetricsUseCase := usecases.NewGetMetricsUseCase(testMetricsRepository)
+	getShippingQuotationUseCase := usecases.NewGetShippingQuotationUseCase(testQuoteRepository, shippingProvider, freteRapidoBreaker, nil, nil)
+	getMetricsUseCase := usecases.NewGetMetricsUseCase(testMetricsRepository, nil)
+	getMetricsSeriesUseCase := usecases.NewGetMetricsSeriesUseCase(testMetricsRepository)
+	listQuotesUseCase := usecases.NewListQuotesUseCase(testQuoteRepository)
+	getQuoteUseCase := usecases.NewGetQuoteUseCase(testQuoteRepository)
+	exportQuotesUseCase := usecases.NewExportQuotesUseCase(testQuoteRepository)
 
 	// Setup router
-	testRouter = routers.SetupRouter(getShippingQuotationUseCase, getMetricsUseCase)
+	testRouter = routers.SetupRouter(getShippingQuotationUseCase, getMetricsUseCase, getMetricsSeriesUseCase, listQuotesUseCase, getQuoteUseCase, exportQuotesUseCase, freteRapidoBreaker)
 
 	return nil
 }
@@ -1,36 +1,14 @@
-// Package docs GENERATED BY SWAGGER - Código gerado automaticamente para a documentação Swagger
-//
-// Documentação da API de Cotação de Frete
-//
-//	Schemes: http
-//	Host: localhost:3000
-//	BasePath: /
-//	Version: 1.0
-//
-//	Consumes:
-//	- application/json
-//
-//	Produces:
-//	- application/json
-//
-// swagger:meta
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
 package docs
 
-import (
-	"bytes"
-	"encoding/json"
-	"strings"
-	"text/template"
+import "github.com/swaggo/swag"
 
-	"github.com/swaggo/swag"
-)
-
-var doc = `{
+const docTemplate = `{
     "schemes": {{ marshal .Schemes }},
     "swagger": "2.0",
     "info": {
-        "description": "API para consulta de valores de frete através de integrações com transportadoras.",
-        "title": "API de Cotação de Frete",
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
         "termsOfService": "http://swagger.io/terms/",
         "contact": {
             "name": "API Support",
@@ -41,19 +19,47 @@ var doc = `{
             "name": "Apache 2.0",
             "url": "http://www.apache.org/licenses/LICENSE-2.0.html"
         },
-        "version": "1.0"
+        "version": "{{.Version}}"
     },
-    "host": "localhost:3000",
-    "basePath": "/",
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
     "paths": {
+        "/diagnostics/circuit-breakers": {
+            "get": {
+                "description": "Retorna o estado (closed, open, half-open) e os contadores de cada circuit breaker",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "diagnóstico"
+                ],
+                "summary": "Obter estado dos circuit breakers",
+                "responses": {
+                    "200": {
+                        "description": "Estado dos circuit breakers",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/circuitbreaker.Snapshot"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/metrics": {
             "get": {
-                "description": "Retorna métricas e estatísticas sobre as cotações de frete realizadas",
+                "description": "Retorna métricas e estatísticas sobre as cotações de frete realizadas, opcionalmente restritas a uma janela de tempo.\nCom Accept text/csv ou application/x-ndjson, retorna uma linha por transportadora (e por região, com group_by).\nCom percentiles=false, a mediana e os percentis são omitidos (colunas vazias no CSV) e, sem from, to, period, last_quotes e group_by (ou apenas com group_by=carrier), as métricas vêm dos agregados acumulados, sem ler as ofertas.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "métricas"
@@ -65,6 +71,61 @@ var doc = `{
                         "description": "Número de cotações recentes a considerar (opcional)",
                         "name": "last_quotes",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do período (RFC 3339 ou AAAA-MM-DD no fuso informado)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fim do período, exclusivo (RFC 3339 ou AAAA-MM-DD, que inclui o dia inteiro)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "last_24h",
+                            "last_7d",
+                            "last_30d",
+                            "today",
+                            "this_month"
+                        ],
+                        "type": "string",
+                        "description": "Período relativo, alternativo a from/to",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuso horário IANA usado nos períodos e datas (padrão: UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dimensões separadas por vírgula: state ou cep_prefix separa por região do destinatário; carrier (padrão), carrier_service ou modal define a chave de cada linha",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dígitos do prefixo do CEP com group_by=cep_prefix (1 a 5, padrão: 3)",
+                        "name": "cep_prefix_length",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Calcula a mediana e os percentis 90 e 95 (padrão: true)",
+                        "name": "percentiles",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de uma resposta anterior; responde 304 se as métricas não mudaram",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -72,24 +133,124 @@ var doc = `{
                         "description": "Métricas de cotações",
                         "schema": {
                             "$ref": "#/definitions/domain.MetricsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Identificador do conteúdo da resposta"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Métricas inalteradas desde o ETag informado"
+                    },
                     "400": {
                         "description": "Erro de parâmetro inválido",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "406": {
+                        "description": "Nenhum formato aceito pelo cabeçalho Accept",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Erros de validação por campo",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Erro interno do servidor",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/timeseries": {
+            "get": {
+                "description": "Retorna, para cada transportadora, a quantidade de cotações e os preços médio, mínimo e máximo por hora, dia ou semana",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "métricas"
+                ],
+                "summary": "Obter série temporal de métricas",
+                "parameters": [
+                    {
+                        "enum": [
+                            "hour",
+                            "day",
+                            "week"
+                        ],
+                        "type": "string",
+                        "description": "Granularidade dos intervalos (padrão: day)",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Início do período (RFC 3339 ou AAAA-MM-DD no fuso informado)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fim do período, exclusivo (RFC 3339 ou AAAA-MM-DD, que inclui o dia inteiro)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "last_24h",
+                            "last_7d",
+                            "last_30d",
+                            "today",
+                            "this_month"
+                        ],
+                        "type": "string",
+                        "description": "Período relativo, alternativo a from/to",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fuso horário IANA usado nos intervalos e datas (padrão: UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Série temporal de métricas",
+                        "schema": {
+                            "$ref": "#/definitions/domain.MetricsSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro de parâmetro inválido",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Erros de validação por campo",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
                         }
                     }
                 }
@@ -117,6 +278,56 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/domain.QuoteRequest"
                         }
+                    },
+                    {
+                        "enum": [
+                            "summary",
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Use 'full' para incluir os detalhes completos de cada oferta",
+                        "name": "detail",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "price",
+                            "deadline"
+                        ],
+                        "type": "string",
+                        "description": "Ordena as ofertas por preço ou prazo",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço máximo aceito",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Prazo máximo aceito, em dias",
+                        "name": "max_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transportadoras permitidas, separadas por vírgula",
+                        "name": "carriers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transportadoras bloqueadas, separadas por vírgula",
+                        "name": "exclude_carriers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Modal aceito, ex.: Rodoviário",
+                        "name": "modal",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -124,70 +335,660 @@ var doc = `{
                         "description": "Cotações de frete disponíveis",
                         "schema": {
                             "$ref": "#/definitions/domain.QuoteResponse"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT quando a cotação foi servida do cache, MISS caso contrário"
+                            }
                         }
                     },
                     "400": {
                         "description": "Erro de requisição inválida",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Erros de validação por campo",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "502": {
+                        "description": "Cotação rejeitada pela transportadora",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Transportadoras temporariamente indisponíveis",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Segundos até uma nova tentativa"
                             }
                         }
                     },
+                    "504": {
+                        "description": "Tempo limite da requisição excedido",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes": {
+            "get": {
+                "description": "Retorna as cotações armazenadas, da mais recente para a mais antiga, paginadas por cursor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cotações"
+                ],
+                "summary": "Listar cotações",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Início do período (RFC 3339 ou AAAA-MM-DD, inclusivo)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fim do período (RFC 3339 exclusivo, ou AAAA-MM-DD inclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome de uma transportadora presente nas ofertas",
+                        "name": "carrier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CEP do destinatário",
+                        "name": "zipcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor retornado em next_cursor pela página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantidade de cotações por página (1 a 100, padrão 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Página de cotações",
+                        "schema": {
+                            "$ref": "#/definitions/domain.QuotePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro de parâmetro inválido",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Erros de validação por parâmetro",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
                     "500": {
                         "description": "Erro interno do servidor",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes/export": {
+            "get": {
+                "description": "Exporta uma linha por oferta de transportadora das cotações armazenadas, da mais antiga para a mais recente, em CSV (padrão) ou NDJSON conforme o cabeçalho Accept. A resposta é enviada em fluxo, sem limite de cotações.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "cotações"
+                ],
+                "summary": "Exportar cotações",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Início do período (RFC 3339 ou AAAA-MM-DD, inclusivo)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fim do período (RFC 3339 exclusivo, ou AAAA-MM-DD inclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exporta apenas as ofertas desta transportadora",
+                        "name": "carrier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CEP do destinatário",
+                        "name": "zipcode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Uma oferta por linha",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.QuoteExportRow"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Erro de parâmetro inválido",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "406": {
+                        "description": "Nenhum formato aceito pelo cabeçalho Accept",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Erros de validação por parâmetro",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/quotes/{id}": {
+            "get": {
+                "description": "Retorna uma cotação armazenada pelo ID, com os detalhes completos das ofertas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cotações"
+                ],
+                "summary": "Obter cotação",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da cotação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cotação armazenada",
+                        "schema": {
+                            "$ref": "#/definitions/domain.QuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Cotação não encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "$ref": "#/definitions/api.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.ProblemDetails": {
+            "description": "Detalhes de um erro da API (RFC 7807)",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Código estável do erro\n@example \"validation_failed\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ErrorCode"
+                        }
+                    ]
+                },
+                "detail": {
+                    "description": "Explicação específica desta ocorrência\n@example \"The quote request has invalid fields\"",
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Violações por campo (apenas para erros de validação)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.InputError"
+                    }
+                },
+                "instance": {
+                    "description": "Caminho da requisição que originou o problema\n@example \"/quote\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Código HTTP\n@example 422",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Resumo do tipo do problema\n@example \"Validation Failed\"",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "URI que identifica o tipo do problema\n@example \"/problems/validation_failed\"",
+                    "type": "string"
+                }
+            }
+        },
+        "circuitbreaker.Snapshot": {
+            "type": "object",
+            "properties": {
+                "failure_ratio": {
+                    "type": "number"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "retry_after_seconds": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Carrier": {
+            "description": "Informações sobre a cotação de uma transportadora específica",
+            "type": "object",
+            "properties": {
+                "applied_rules": {
+                    "description": "Regras aplicadas ao frete, presentes quando solicitadas em returns.applied_rules.\nO formato varia conforme o tipo de regra e é repassado como recebido da transportadora.",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "cheapest": {
+                    "description": "Indica a oferta mais barata da resposta\n@example true",
+                    "type": "boolean"
+                },
+                "composition": {
+                    "description": "Composição do valor do frete, presente quando solicitada em returns.composition",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.OfferComposition"
+                        }
+                    ]
+                },
+                "deadline": {
+                    "description": "Prazo de entrega em dias\n@example \"3\"",
+                    "type": "string"
+                },
+                "deadline_days": {
+                    "description": "Prazo de entrega em dias (numérico)\n@example 3",
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Detalhes completos da oferta, retornados apenas com ?detail=full",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CarrierDetails"
+                        }
+                    ]
+                },
+                "dispatcher": {
+                    "description": "Centro de distribuição de origem da oferta",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CarrierDispatcher"
+                        }
+                    ]
+                },
+                "estimated_delivery_date": {
+                    "description": "Data estimada de entrega\n@example \"2025-01-13T00:00:00Z\"",
+                    "type": "string"
+                },
+                "fastest": {
+                    "description": "Indica a oferta mais rápida da resposta\n@example false",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Nome da transportadora\n@example \"EXPRESSO FR\"",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Valor do frete\n@example 17.00",
+                    "type": "number"
+                },
+                "service": {
+                    "description": "Serviço oferecido\n@example \"Rodoviário\"",
+                    "type": "string"
+                },
+                "simulation_type": {
+                    "description": "Tipo de simulação da oferta: 0 = fracionada (omitido), 1 = lotação\n@example 1",
+                    "type": "integer"
+                },
+                "volumes": {
+                    "description": "Volumes considerados no cálculo, presentes quando solicitados em returns.volumes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.OfferVolume"
+                    }
+                }
+            }
+        },
+        "domain.CarrierDetails": {
+            "description": "Detalhes completos de uma oferta de frete",
+            "type": "object",
+            "properties": {
+                "company_name": {
+                    "description": "Razão social da transportadora\n@example \"EXPRESSO FR LTDA\"",
+                    "type": "string"
+                },
+                "cost_price": {
+                    "description": "Valor de custo do frete\n@example 15.50",
+                    "type": "number"
+                },
+                "estimated_date": {
+                    "description": "Data estimada de entrega informada pela transportadora\n@example \"2025-01-13\"",
+                    "type": "string"
+                },
+                "expiration": {
+                    "description": "Validade da oferta\n@example \"2025-01-10T12:00:00Z\"",
+                    "type": "string"
+                },
+                "final_price": {
+                    "description": "Valor final do frete\n@example 17.00",
+                    "type": "number"
+                },
+                "home_delivery": {
+                    "description": "Indica se a entrega é feita em domicílio\n@example true",
+                    "type": "boolean"
+                },
+                "logo": {
+                    "description": "URL do logotipo da transportadora\n@example \"https://s3.amazonaws.com/public.prod.freterapido.uploads/transportadora/foto-perfil/25438296000158.png\"",
+                    "type": "string"
+                },
+                "modal": {
+                    "description": "Modal de transporte\n@example \"Rodoviário\"",
+                    "type": "string"
+                },
+                "registered_number": {
+                    "description": "CNPJ da transportadora\n@example \"25438296000158\"",
+                    "type": "string"
+                },
+                "weights": {
+                    "description": "Pesos considerados no cálculo",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CarrierWeights"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.CarrierDispatcher": {
+            "description": "Centro de distribuição de origem de uma oferta",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Identificador do expedidor na simulação do Frete Rápido\n@example \"67a0f2c8e4b0a1b2c3d4e5f6\"",
+                    "type": "string"
+                },
+                "registered_number": {
+                    "description": "CNPJ do expedidor\n@example \"25438296000158\"",
+                    "type": "string"
+                },
+                "zipcode": {
+                    "description": "CEP de origem\n@example \"29161376\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CarrierSeries": {
+            "description": "Série temporal de métricas de uma transportadora",
+            "type": "object",
+            "properties": {
+                "carrier_name": {
+                    "description": "Nome da transportadora\n@example \"EXPRESSO FR\"",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "Intervalos com cotações, em ordem cronológica; intervalos sem cotações são omitidos",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MetricsPoint"
+                    }
+                }
+            }
+        },
+        "domain.CarrierWeights": {
+            "description": "Pesos real, cubado e utilizado no cálculo (kg)",
+            "type": "object",
+            "properties": {
+                "cubed": {
+                    "description": "Peso cubado\n@example 2.4",
+                    "type": "number"
+                },
+                "real": {
+                    "description": "Peso real\n@example 5.0",
+                    "type": "number"
+                },
+                "used": {
+                    "description": "Peso utilizado no cálculo\n@example 5.0",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.CheapestAndMostExpensive": {
+            "description": "Valores mínimos e máximos encontrados nas cotações",
+            "type": "object",
+            "properties": {
+                "cheapest_shipping": {
+                    "description": "Valor do frete mais barato\n@example 12.50",
+                    "type": "number"
+                },
+                "most_expensive_shipping": {
+                    "description": "Valor do frete mais caro\n@example 30.75",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.Dispatcher": {
+            "description": "Centro de distribuição de origem de uma cotação",
+            "type": "object",
+            "properties": {
+                "registered_number": {
+                    "description": "CNPJ do expedidor (padrão: CNPJ configurado)\n@example \"25438296000158\"",
+                    "type": "string"
+                },
+                "volumes": {
+                    "description": "Volumes expedidos por este centro de distribuição",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Volume"
                     }
+                },
+                "zipcode": {
+                    "description": "CEP de origem (padrão: CEP configurado)\n@example \"29161376\"",
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Carrier": {
-            "description": "Informações sobre a cotação de uma transportadora específica",
+        },
+        "domain.DispatcherQuote": {
+            "description": "Ofertas de frete de um centro de distribuição",
             "type": "object",
             "properties": {
-                "deadline": {
-                    "description": "Prazo de entrega em dias",
-                    "type": "string",
-                    "example": "3"
+                "carrier": {
+                    "description": "Ofertas deste centro de distribuição",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Carrier"
+                    }
                 },
-                "name": {
-                    "description": "Nome da transportadora",
-                    "type": "string",
-                    "example": "EXPRESSO FR"
+                "id": {
+                    "description": "Identificador do expedidor na simulação do Frete Rápido\n@example \"67a0f2c8e4b0a1b2c3d4e5f6\"",
+                    "type": "string"
                 },
-                "price": {
-                    "description": "Valor do frete",
-                    "type": "number",
-                    "example": 17
+                "registered_number": {
+                    "description": "CNPJ do expedidor\n@example \"25438296000158\"",
+                    "type": "string"
                 },
-                "service": {
-                    "description": "Serviço oferecido",
-                    "type": "string",
-                    "example": "Rodoviário"
+                "zipcode": {
+                    "description": "CEP de origem\n@example \"29161376\"",
+                    "type": "string"
                 }
             }
         },
-        "domain.CheapestAndMostExpensive": {
-            "description": "Valores mínimos e máximos encontrados nas cotações",
+        "domain.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "invalid_request",
+                "validation_failed",
+                "upstream_unavailable",
+                "upstream_rejected",
+                "persistence_failure",
+                "not_found",
+                "not_acceptable",
+                "timeout",
+                "internal_error"
+            ],
+            "x-enum-varnames": [
+                "CodeInvalidRequest",
+                "CodeValidationFailed",
+                "CodeUpstreamUnavailable",
+                "CodeUpstreamRejected",
+                "CodePersistenceFailure",
+                "CodeNotFound",
+                "CodeNotAcceptable",
+                "CodeTimeout",
+                "CodeInternal"
+            ]
+        },
+        "domain.InputError": {
+            "description": "Erro de validação de um campo da requisição",
             "type": "object",
             "properties": {
-                "cheapest_shipping": {
-                    "description": "Valor do frete mais barato",
-                    "type": "number",
-                    "example": 12.5
+                "field": {
+                    "description": "Caminho do campo inválido\n@example \"volumes[0].unitary_weight\"",
+                    "type": "string"
                 },
-                "most_expensive_shipping": {
-                    "description": "Valor do frete mais caro",
-                    "type": "number",
-                    "example": 30.75
+                "message": {
+                    "description": "Descrição da violação\n@example \"must be greater than 0\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MetricsBucket": {
+            "type": "string",
+            "enum": [
+                "hour",
+                "day",
+                "week"
+            ],
+            "x-enum-varnames": [
+                "BucketHour",
+                "BucketDay",
+                "BucketWeek"
+            ]
+        },
+        "domain.MetricsPeriod": {
+            "type": "string",
+            "enum": [
+                "last_24h",
+                "last_7d",
+                "last_30d",
+                "today",
+                "this_month"
+            ],
+            "x-enum-varnames": [
+                "PeriodLast24Hours",
+                "PeriodLast7Days",
+                "PeriodLast30Days",
+                "PeriodToday",
+                "PeriodThisMonth"
+            ]
+        },
+        "domain.MetricsPoint": {
+            "description": "Métricas de uma transportadora em um intervalo da série",
+            "type": "object",
+            "properties": {
+                "average_shipping_price": {
+                    "description": "Valor médio dos fretes no intervalo\n@example 18.40",
+                    "type": "number"
+                },
+                "bucket_start": {
+                    "description": "Início do intervalo, no fuso horário da consulta\n@example \"2025-01-13T00:00:00-03:00\"",
+                    "type": "string"
+                },
+                "max_shipping_price": {
+                    "description": "Maior valor de frete no intervalo\n@example 27.90",
+                    "type": "number"
+                },
+                "min_shipping_price": {
+                    "description": "Menor valor de frete no intervalo\n@example 12.50",
+                    "type": "number"
+                },
+                "total_quotes": {
+                    "description": "Total de cotações no intervalo\n@example 12",
+                    "type": "integer"
                 }
             }
         },
@@ -196,41 +997,322 @@ var doc = `{
             "type": "object",
             "properties": {
                 "carrier_metrics": {
-                    "description": "Lista de métricas por transportadora",
+                    "description": "Métricas por transportadora\n@Description Lista de métricas por transportadora",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/domain.QuoteMetrics"
                     }
                 },
                 "cheapest_and_most_expensive": {
-                    "description": "Detalhes sobre os valores mínimos e máximos de frete",
-                    "$ref": "#/definitions/domain.CheapestAndMostExpensive"
+                    "description": "Informações sobre cotações mais baratas e mais caras\n@Description Detalhes sobre os valores mínimos e máximos de frete",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CheapestAndMostExpensive"
+                        }
+                    ]
+                },
+                "regions": {
+                    "description": "Métricas separadas por região do destinatário, ausentes sem group_by ou sem cotações\n@Description Métricas por transportadora em cada região, em ordem alfabética",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RegionMetrics"
+                    }
+                },
+                "window": {
+                    "description": "Janela de cotações considerada no cálculo\n@Description Período, fuso horário e limite de cotações aplicados",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MetricsWindow"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.MetricsSeries": {
+            "description": "Métricas por transportadora agrupadas em intervalos de tempo",
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "description": "Granularidade dos intervalos\n@example \"day\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MetricsBucket"
+                        }
+                    ]
+                },
+                "carriers": {
+                    "description": "Séries por transportadora, em ordem alfabética",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CarrierSeries"
+                    }
+                },
+                "window": {
+                    "description": "Janela de cotações considerada",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MetricsWindow"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.MetricsWindow": {
+            "description": "Janela de cotações considerada nas métricas",
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "Início do período (inclusivo), ausente quando não há limite inferior\n@example \"2025-01-01T00:00:00-03:00\"",
+                    "type": "string"
+                },
+                "last_quotes": {
+                    "description": "Quantidade de cotações mais recentes consideradas, ausente quando todas\n@example 10",
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "Período relativo solicitado\n@example \"this_month\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MetricsPeriod"
+                        }
+                    ]
+                },
+                "timezone": {
+                    "description": "Fuso horário da consulta\n@example \"America/Sao_Paulo\"",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Fim do período (exclusivo), ausente quando não há limite superior\n@example \"2025-02-01T00:00:00-03:00\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.OfferComposition": {
+            "description": "Composição do valor do frete",
+            "type": "object",
+            "properties": {
+                "freight_invoice": {
+                    "description": "Frete valor (ad valorem)",
+                    "type": "number"
+                },
+                "freight_minimum": {
+                    "description": "Frete mínimo",
+                    "type": "number"
+                },
+                "freight_volume": {
+                    "description": "Frete por volume",
+                    "type": "number"
+                },
+                "freight_weight": {
+                    "description": "Frete peso\n@example 61.83",
+                    "type": "number"
+                },
+                "freight_weight_excess": {
+                    "description": "Frete peso excedente",
+                    "type": "number"
+                },
+                "freight_weight_volume": {
+                    "description": "Frete peso por volume",
+                    "type": "number"
+                },
+                "sub_total1": {
+                    "description": "Taxas do primeiro subtotal (coleta, entrega, pedágio...), por nome",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "sub_total2": {
+                    "description": "Taxas do segundo subtotal (TRT, TDA, TDE...), por nome",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "sub_total3": {
+                    "description": "Taxas do terceiro subtotal (seguro, GRIS...), por nome",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "domain.OfferVolume": {
+            "description": "Volume considerado no cálculo do frete",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Quantidade de itens\n@example 1",
+                    "type": "integer"
+                },
+                "category": {
+                    "description": "Categoria do produto\n@example \"7\"",
+                    "type": "string"
+                },
+                "height": {
+                    "description": "Altura em metros",
+                    "type": "number"
+                },
+                "length": {
+                    "description": "Comprimento em metros",
+                    "type": "number"
+                },
+                "sku": {
+                    "description": "Código SKU do produto\n@example \"abc-teste-123\"",
+                    "type": "string"
+                },
+                "unitary_price": {
+                    "description": "Preço unitário",
+                    "type": "number"
+                },
+                "unitary_weight": {
+                    "description": "Peso unitário em kg",
+                    "type": "number"
+                },
+                "width": {
+                    "description": "Largura em metros",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.QuoteExportRow": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "description": "Nome da transportadora",
+                    "type": "string"
+                },
+                "cheapest": {
+                    "description": "Indica a oferta mais barata da cotação",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "description": "Data da cotação",
+                    "type": "string"
+                },
+                "deadline_days": {
+                    "description": "Prazo de entrega em dias",
+                    "type": "integer"
+                },
+                "estimated_delivery_date": {
+                    "description": "Data estimada de entrega, quando informada",
+                    "type": "string"
+                },
+                "fastest": {
+                    "description": "Indica a oferta mais rápida da cotação",
+                    "type": "boolean"
+                },
+                "modal": {
+                    "description": "Modal de transporte, vazio quando a transportadora não informa",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Valor do frete",
+                    "type": "number"
+                },
+                "quote_id": {
+                    "description": "Cotação à qual a oferta pertence",
+                    "type": "integer"
+                },
+                "recipient_zipcode": {
+                    "description": "CEP do destinatário",
+                    "type": "string"
+                },
+                "service": {
+                    "description": "Serviço da oferta",
+                    "type": "string"
                 }
             }
         },
         "domain.QuoteMetrics": {
-            "description": "Métricas de cotações para uma transportadora específica",
+            "description": "Métricas de cotações para uma transportadora, um serviço ou um modal",
             "type": "object",
             "properties": {
+                "average_delivery_days": {
+                    "description": "Prazo médio de entrega, em dias\n@example 3.4",
+                    "type": "number"
+                },
                 "average_shipping_price": {
-                    "description": "Valor médio dos fretes cotados",
-                    "type": "number",
-                    "example": 15.05
+                    "description": "Valor médio dos fretes cotados\n@example 15.05",
+                    "type": "number"
                 },
                 "carrier_name": {
-                    "description": "Nome da transportadora",
-                    "type": "string",
-                    "example": "EXPRESSO FR"
+                    "description": "Nome da transportadora, ausente quando as métricas são agregadas por modal\n@example \"EXPRESSO FR\"",
+                    "type": "string"
+                },
+                "cheapest_count": {
+                    "description": "Quantidade de cotações em que a transportadora teve a oferta mais barata (empates contam para todas)\n@example 4",
+                    "type": "integer"
+                },
+                "cheapest_share": {
+                    "description": "Fração, entre 0 e 1, das cotações com oferta da transportadora em que ela foi a mais barata\n@example 0.4",
+                    "type": "number"
+                },
+                "max_delivery_days": {
+                    "description": "Maior prazo de entrega, em dias\n@example 7",
+                    "type": "integer"
+                },
+                "max_shipping_price": {
+                    "description": "Maior valor de frete cotado\n@example 27.90",
+                    "type": "number"
+                },
+                "median_shipping_price": {
+                    "description": "Mediana dos valores de frete, ausente com percentiles=false\n@example 14.80",
+                    "type": "number"
+                },
+                "min_delivery_days": {
+                    "description": "Menor prazo de entrega, em dias\n@example 1",
+                    "type": "integer"
+                },
+                "min_shipping_price": {
+                    "description": "Menor valor de frete cotado\n@example 12.50",
+                    "type": "number"
+                },
+                "modal": {
+                    "description": "Modal de transporte, presente quando as métricas são agregadas por modal\n@example \"Rodoviário\"",
+                    "type": "string"
+                },
+                "p90_shipping_price": {
+                    "description": "Percentil 90 dos valores de frete, ausente com percentiles=false\n@example 22.10",
+                    "type": "number"
+                },
+                "p95_shipping_price": {
+                    "description": "Percentil 95 dos valores de frete, ausente com percentiles=false\n@example 25.30",
+                    "type": "number"
+                },
+                "service": {
+                    "description": "Serviço, presente quando as métricas são agregadas por transportadora e serviço\n@example \"Rodoviário\"",
+                    "type": "string"
+                },
+                "stddev_shipping_price": {
+                    "description": "Desvio padrão (populacional) dos valores de frete\n@example 4.12",
+                    "type": "number"
                 },
                 "total_quotes": {
-                    "description": "Total de cotações realizadas",
-                    "type": "integer",
-                    "example": 10
+                    "description": "Total de cotações realizadas\n@example 10",
+                    "type": "integer"
                 },
                 "total_shipping_price": {
-                    "description": "Valor total dos fretes cotados",
-                    "type": "number",
-                    "example": 150.5
+                    "description": "Valor total dos fretes cotados\n@example 150.50",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.QuotePage": {
+            "description": "Página do histórico de cotações, da mais recente para a mais antiga",
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Cursor para a próxima página, ausente na última página\n@example \"MjAyNS0wMS0xMFQxMjowMDowMFp8NDI\"",
+                    "type": "string"
+                },
+                "quotes": {
+                    "description": "Cotações da página",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.QuoteResponse"
+                    }
                 }
             }
         },
@@ -238,6 +1320,13 @@ var doc = `{
             "description": "Solicitação para obter cotações de frete de diferentes transportadoras",
             "type": "object",
             "properties": {
+                "dispatchers": {
+                    "description": "Centros de distribuição de origem, cada um com os seus volumes (opcional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Dispatcher"
+                    }
+                },
                 "recipient": {
                     "description": "Informações do destinatário",
                     "type": "object",
@@ -247,16 +1336,42 @@ var doc = `{
                             "type": "object",
                             "properties": {
                                 "zipcode": {
-                                    "description": "CEP do destinatário (obrigatório)",
-                                    "type": "string",
-                                    "example": "01311000"
+                                    "description": "CEP do destinatário (obrigatório)\n@example \"01311000\"",
+                                    "type": "string"
                                 }
                             }
+                        },
+                        "registered_number": {
+                            "description": "CPF ou CNPJ do destinatário (obrigatório para pessoa jurídica)\n@example \"11222333000181\"",
+                            "type": "string"
+                        },
+                        "state_inscription": {
+                            "description": "Inscrição estadual do destinatário (pessoa jurídica)\n@example \"123456789\"",
+                            "type": "string"
+                        },
+                        "type": {
+                            "description": "Tipo do destinatário: 0 = pessoa física, 1 = pessoa jurídica\n@example 0",
+                            "type": "integer"
+                        }
+                    }
+                },
+                "returns": {
+                    "description": "Informações adicionais a retornar em cada oferta (opcional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.QuoteReturns"
                         }
+                    ]
+                },
+                "simulation_type": {
+                    "description": "Tipos de simulação: 0 = fracionada, 1 = lotação (padrão: [0])\n@example [0]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
                 },
                 "volumes": {
-                    "description": "Lista de volumes para cálculo de frete",
+                    "description": "Lista de volumes para transporte, expedidos pelo centro de distribuição padrão\n@Description Lista de volumes para cálculo de frete",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/domain.Volume"
@@ -269,11 +1384,183 @@ var doc = `{
             "type": "object",
             "properties": {
                 "carrier": {
-                    "description": "Lista de transportadoras e seus valores",
+                    "description": "Lista de transportadoras com suas cotações\n@Description Lista de transportadoras e seus valores",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/domain.Carrier"
                     }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "dispatchers": {
+                    "description": "Ofertas agrupadas por centro de distribuição, presente quando a solicitação informa dispatchers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DispatcherQuote"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "recipient_zipcode": {
+                    "description": "CEP do destinatário da cotação\n@example \"01311000\"",
+                    "type": "string"
+                },
+                "request": {
+                    "description": "Solicitação que originou a cotação, presente nas consultas ao histórico",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.StoredQuoteRequest"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.QuoteReturns": {
+            "description": "Informações adicionais a retornar em cada oferta",
+            "type": "object",
+            "properties": {
+                "applied_rules": {
+                    "description": "Regras aplicadas ao frete\n@example false",
+                    "type": "boolean"
+                },
+                "composition": {
+                    "description": "Composição do valor do frete\n@example false",
+                    "type": "boolean"
+                },
+                "volumes": {
+                    "description": "Volumes considerados no cálculo\n@example false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.RegionMetrics": {
+            "description": "Métricas por transportadora para uma região de destino",
+            "type": "object",
+            "properties": {
+                "carrier_metrics": {
+                    "description": "Métricas por transportadora na região",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.QuoteMetrics"
+                    }
+                },
+                "cheapest_and_most_expensive": {
+                    "description": "Fretes mais barato e mais caro na região",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CheapestAndMostExpensive"
+                        }
+                    ]
+                },
+                "region": {
+                    "description": "UF ou prefixo de CEP, ou \"unknown\" quando o CEP não pertence a nenhuma região\n@example \"BA\"",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StoredQuoteRequest": {
+            "description": "Solicitação que originou uma cotação armazenada",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@example 42",
+                    "type": "integer"
+                },
+                "quote_id": {
+                    "description": "Cotação gerada por esta solicitação\n@example 42",
+                    "type": "integer"
+                },
+                "recipient_registered_number": {
+                    "description": "CPF ou CNPJ do destinatário, sem pontuação\n@example \"11222333000181\"",
+                    "type": "string"
+                },
+                "recipient_type": {
+                    "description": "Tipo do destinatário: 0 = pessoa física, 1 = pessoa jurídica\n@example 0",
+                    "type": "integer"
+                },
+                "recipient_zipcode": {
+                    "description": "CEP do destinatário\n@example \"01311000\"",
+                    "type": "string"
+                },
+                "total_cubage": {
+                    "description": "Cubagem total em m³\n@example 0.08",
+                    "type": "number"
+                },
+                "total_value": {
+                    "description": "Valor total da mercadoria\n@example 1461.0",
+                    "type": "number"
+                },
+                "total_weight": {
+                    "description": "Peso total em kg\n@example 13.0",
+                    "type": "number"
+                },
+                "volume_count": {
+                    "description": "Quantidade total de itens\n@example 3",
+                    "type": "integer"
+                },
+                "volumes": {
+                    "description": "Volumes da solicitação",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StoredQuoteVolume"
+                    }
+                }
+            }
+        },
+        "domain.StoredQuoteVolume": {
+            "description": "Volume de uma solicitação de cotação armazenada",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "@example 1",
+                    "type": "integer"
+                },
+                "category": {
+                    "description": "@example 7",
+                    "type": "integer"
+                },
+                "dispatcher_registered_number": {
+                    "description": "CNPJ do centro de distribuição, vazio para o padrão\n@example \"25438296000158\"",
+                    "type": "string"
+                },
+                "dispatcher_zipcode": {
+                    "description": "CEP de origem, vazio para o padrão\n@example \"29161376\"",
+                    "type": "string"
+                },
+                "height": {
+                    "description": "@example 0.2",
+                    "type": "number"
+                },
+                "length": {
+                    "description": "@example 0.2",
+                    "type": "number"
+                },
+                "price": {
+                    "description": "@example 349.90",
+                    "type": "number"
+                },
+                "sku": {
+                    "description": "@example \"abc-teste-123\"",
+                    "type": "string"
+                },
+                "unitary_weight": {
+                    "description": "@example 5.0",
+                    "type": "number"
+                },
+                "width": {
+                    "description": "@example 0.2",
+                    "type": "number"
                 }
             }
         },
@@ -282,93 +1569,68 @@ var doc = `{
             "type": "object",
             "properties": {
                 "amount": {
-                    "description": "Quantidade de itens",
-                    "type": "integer",
-                    "example": 1
+                    "description": "Quantidade de itens\n@example 1",
+                    "type": "integer"
                 },
                 "category": {
-                    "description": "Categoria do produto",
-                    "type": "integer",
-                    "example": 7
+                    "description": "Categoria do produto\n@example 7",
+                    "type": "integer"
                 },
                 "height": {
-                    "description": "Altura do volume em metros",
-                    "type": "number",
-                    "example": 0.2
+                    "description": "Altura do volume em metros\n@example 0.2",
+                    "type": "number"
                 },
                 "length": {
-                    "description": "Comprimento do volume em metros",
-                    "type": "number",
-                    "example": 0.2
+                    "description": "Comprimento do volume em metros\n@example 0.2",
+                    "type": "number"
                 },
                 "price": {
-                    "description": "Preço unitário do produto",
-                    "type": "number",
-                    "example": 349.9
+                    "description": "Preço unitário do produto\n@example 349.90",
+                    "type": "number"
                 },
                 "sku": {
-                    "description": "Código SKU do produto",
-                    "type": "string",
-                    "example": "abc-teste-123"
+                    "description": "Código SKU do produto\n@example \"abc-teste-123\"",
+                    "type": "string"
                 },
                 "unitary_weight": {
-                    "description": "Peso unitário em kg",
-                    "type": "number",
-                    "example": 5
+                    "description": "Peso unitário em kg\n@example 5.0",
+                    "type": "number"
                 },
                 "width": {
-                    "description": "Largura do volume em metros",
-                    "type": "number",
-                    "example": 0.2
+                    "description": "Largura do volume em metros\n@example 0.2",
+                    "type": "number"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
                 }
             }
         }
     }
 }`
 
-type swaggerInfo struct {
-	Version     string
-	Host        string
-	BasePath    string
-	Schemes     []string
-	Title       string
-	Description string
-}
-
-// SwaggerInfo contém as informações básicas da API
-var SwaggerInfo = swaggerInfo{
-	Version:     "1.0",
-	Host:        "localhost:3000",
-	BasePath:    "/",
-	Schemes:     []string{"http"},
-	Title:       "API de Cotação de Frete",
-	Description: "API para consulta de valores de frete através de integrações com transportadoras.",
-}
-
-type s struct{}
-
-func (s *s) ReadDoc() string {
-	sInfo := SwaggerInfo
-	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
-
-	t, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-			return string(a)
-		},
-	}).Parse(doc)
-	if err != nil {
-		return doc
-	}
-
-	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, sInfo); err != nil {
-		return doc
-	}
-
-	return tpl.String()
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "localhost:3000",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "API de Cotação de Frete",
+	Description:      "API para consulta de valores de frete através de integrações com transportadoras.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
 }
 
 func init() {
-	swag.Register(swag.Name, &s{})
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
 }